@@ -0,0 +1,23 @@
+package v2alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIKeySecretLabel marks the Secrets holding API keys. Only the Secrets carrying it, whatever its value, are watched
+// by the controller and selected by the APIKEY strategy.
+const APIKeySecretLabel = "gateway.kyma-project.io/api-key"
+
+// APIKeyModeConfig Config for APIKEY mode, accepting the requests carrying one of the keys stored in the selected
+// Secrets. The key is read from the header, or from the query parameter if one is set.
+type APIKeyModeConfig struct {
+	// Request header carrying the key, defaults to x-api-key
+	// +optional
+	Header string `json:"header,omitempty"`
+	// Query parameter carrying the key instead of a header
+	// +optional
+	QueryParam string `json:"queryParam,omitempty"`
+	// Selector of the Secrets in the namespace of the Gate holding the accepted keys in their api-key entry. Only the
+	// Secrets labeled with gateway.kyma-project.io/api-key are selected.
+	SecretSelector *metav1.LabelSelector `json:"secretSelector"`
+}
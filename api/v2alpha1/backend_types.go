@@ -0,0 +1,23 @@
+package v2alpha1
+
+// Backend Service version receiving a share of the requests not matched by the routes of the Gate
+type Backend struct {
+	// Name of the service, defaults to the service of the Gate
+	// +optional
+	Name *string `json:"name,omitempty"`
+	// Port of the service, defaults to the port of the service of the Gate
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99999
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// Name of the version of the service, generated as a subset of its DestinationRule
+	// +optional
+	Subset string `json:"subset,omitempty"`
+	// Labels of the pods of the version
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Percentage of the requests forwarded to the backend
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+}
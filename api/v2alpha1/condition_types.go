@@ -0,0 +1,51 @@
+package v2alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType Type of a condition of the Gate
+type ConditionType string
+
+// ConditionStatus Status of a condition, one of True, False or Unknown
+type ConditionStatus string
+
+const (
+	// ConditionReady The Gate is exposed as specified
+	ConditionReady ConditionType = "Ready"
+	// ConditionValidated The spec of the Gate is valid
+	ConditionValidated ConditionType = "Validated"
+	// ConditionVirtualServiceReady The Istio Virtual Service of the Gate is up to date
+	ConditionVirtualServiceReady ConditionType = "VirtualServiceReady"
+	// ConditionAccessRulesReady The Oathkeeper Access Rules of the Gate are up to date
+	ConditionAccessRulesReady ConditionType = "AccessRulesReady"
+	// ConditionPolicyReady The Istio RequestAuthentication and AuthorizationPolicy of the Gate are up to date
+	ConditionPolicyReady ConditionType = "PolicyReady"
+	// ConditionRateLimitReady The Istio Envoy Filter limiting the rate of the requests to the Gate is up to date
+	ConditionRateLimitReady ConditionType = "RateLimitReady"
+	// ConditionTLSReady The Istio Gateway and the certificate exposing the Gate over TLS are up to date
+	ConditionTLSReady ConditionType = "TLSReady"
+
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition Observation of the state of the Gate, following the Kubernetes conventions for conditions
+type Condition struct {
+	// Type of the condition
+	Type ConditionType `json:"type"`
+	// Status of the condition, one of True, False or Unknown
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status ConditionStatus `json:"status"`
+	// Generation of the Gate the condition was set for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Last time the status of the condition changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// Reason of the last transition in CamelCase
+	Reason string `json:"reason"`
+	// Human readable details of the last transition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,23 @@
+package v2alpha1
+
+// CorsPolicy Cross-origin resource sharing policy applied to the requests sent to the Gate
+type CorsPolicy struct {
+	// Origins allowed to perform CORS requests, * allows all origins
+	// +kubebuilder:validation:MinItems=1
+	AllowOrigins []string `json:"allowOrigins"`
+	// HTTP methods allowed to access the service
+	// +optional
+	AllowMethods []string `json:"allowMethods,omitempty"`
+	// HTTP headers which can be used when requesting the service
+	// +optional
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+	// HTTP headers the browsers are allowed to access
+	// +optional
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+	// How long the results of a preflight request can be cached, e.g. 24h
+	// +optional
+	MaxAge string `json:"maxAge,omitempty"`
+	// Defines if the actual request can be sent with credentials
+	// +optional
+	AllowCredentials *bool `json:"allowCredentials,omitempty"`
+}
@@ -26,6 +26,8 @@ const (
 	JWT            string     = "JWT"
 	OAUTH          string     = "OAUTH"
 	PASSTHROUGH    string     = "PASSTHROUGH"
+	MTLS           string     = "MTLS"
+	APIKEY         string     = "APIKEY"
 	STATUS_OK      StatusCode = "OK"
 	STATUS_SKIPPED StatusCode = "SKIPPED"
 	STATUS_ERROR   StatusCode = "ERROR"
@@ -37,9 +39,35 @@ type GateSpec struct {
 	Service *Service `json:"service"`
 	// Auth strategy to be used
 	Auth *AuthStrategy `json:"auth"`
-	// Gateway to be used
+	// Gateway to be used, defaults to the default gateway of the cluster
 	// +kubebuilder:validation:Pattern=^(?:[_a-z0-9](?:[_a-z0-9-]+[a-z0-9])?\.)+(?:[a-z](?:[a-z0-9-]+[a-z0-9])?)?$
-	Gateway *string `json:"gateway"`
+	// +optional
+	Gateway *string `json:"gateway,omitempty"`
+	// TLS configuration of the host of the Gate, exposed through a Gateway generated with the selector of the gateway
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+	// Routes forwarding the matching paths to other services than the default one.
+	// The most specific match takes precedence, the default service handles the remaining paths.
+	// With the JWT strategy, the routes can only forward to the service of the Gate, where the tokens are verified.
+	// +optional
+	Routes []Route `json:"routes,omitempty"`
+	// Backends splitting the requests not matched by the routes between service versions, instead of the service of the Gate.
+	// The weights of the backends must add up to 100. With the JWT strategy, the backends can only select versions of
+	// the service of the Gate, where the tokens are verified.
+	// +optional
+	Backends []Backend `json:"backends,omitempty"`
+	// Rate limit applied to the requests sent to the Gate
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// Cross-origin resource sharing policy of the Gate
+	// +optional
+	Cors *CorsPolicy `json:"cors,omitempty"`
+	// Timeout, retries and fault injection of the requests sent to the Gate, which the routes can override
+	// +optional
+	Traffic *TrafficPolicy `json:"traffic,omitempty"`
+	// Headers of the requests and responses of the Gate, which the routes can extend
+	// +optional
+	Headers *Headers `json:"headers,omitempty"`
 }
 
 // GateStatus defines the observed state of Gate
@@ -50,6 +78,14 @@ type GateStatus struct {
 	VirtualServiceStatus *GatewayResourceStatus `json:"virtualServiceStatus,omitempty"`
 	PolicyServiceStatus  *GatewayResourceStatus `json:"policyStatus,omitempty"`
 	AccessRuleStatus     *GatewayResourceStatus `json:"accessRuleStatus,omitempty"`
+	RateLimitStatus      *GatewayResourceStatus `json:"rateLimitStatus,omitempty"`
+	TLSStatus            *GatewayResourceStatus `json:"tlsStatus,omitempty"`
+	// Number of consecutive retries after transient errors, reset once the Gate is processed
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty"`
+	// Conditions of the Gate, maintained alongside the statuses of the generated resources
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:storageversion
@@ -74,28 +110,116 @@ type GateList struct {
 }
 
 type Service struct {
-	// Name of the service
+	// Name of the service, or its fully qualified domain name if the service is external
 	Name *string `json:"name"`
-	// Port of the service to expose
+	// Port of the service to expose, defaults to the only port of the service
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=99999
-	Port *int32 `json:"port"`
+	// +optional
+	Port *int32 `json:"port,omitempty"`
 	// URL on which the service will be visible
 	// +kubebuilder:validation:MinLength=3
 	// +kubebuilder:validation:MaxLength=256
 	// +kubebuilder:validation:Pattern=^(?:[_a-z0-9](?:[_a-z0-9-]+[a-z0-9])?\.)+(?:[a-z](?:[a-z0-9-]+[a-z0-9])?)?$
 	Host *string `json:"host"`
-	// Defines if the service is internal (in cluster) or external
+	// Defines if the service is internal (in cluster) or external. External services cannot be exposed with the JWT
+	// strategy, whose tokens are verified by the sidecar of the service.
 	// +optional
 	IsExternal *bool `json:"external,omitempty"`
+	// Defines if the connection to an external service is upgraded to TLS
+	// +optional
+	OriginateTLS *bool `json:"originateTls,omitempty"`
+}
+
+// Route Forwards the requests matching the path to the given service
+type Route struct {
+	// Path matched by the route
+	Path *StringMatch `json:"path"`
+	// Service the matching requests are forwarded to
+	Service *RouteService `json:"service"`
+	// Timeout, retries and fault injection of the route, overriding the ones of the Gate. Not supported by the OAUTH
+	// strategy, whose requests are forwarded by the auth proxy.
+	// +optional
+	Traffic *TrafficPolicy `json:"traffic,omitempty"`
+	// Rewrite of the requests matching the route. Not supported by the OAUTH strategy.
+	// +optional
+	Rewrite *Rewrite `json:"rewrite,omitempty"`
+	// Headers of the requests and responses of the route, extending the ones of the Gate. Not supported by the OAUTH
+	// strategy.
+	// +optional
+	Headers *Headers `json:"headers,omitempty"`
+	// Conditions the requests matching the path must also meet, e.g. for A/B testing
+	// +optional
+	Conditions *RouteConditions `json:"conditions,omitempty"`
+}
+
+// RouteConditions Conditions on the headers, query parameters and cookies of the requests matched by a route.
+// All the conditions must be met.
+type RouteConditions struct {
+	// Matches of the request headers by their lowercase name
+	// +optional
+	Headers map[string]StringMatch `json:"headers,omitempty"`
+	// Match of a query parameter. A single one is supported, as it is matched within the path header.
+	// +optional
+	QueryParam *NamedStringMatch `json:"queryParam,omitempty"`
+	// Match of a cookie. A single one is supported, as it is matched within the cookie header.
+	// +optional
+	Cookie *NamedStringMatch `json:"cookie,omitempty"`
+}
+
+// NamedStringMatch Match of the value of a query parameter or cookie
+type NamedStringMatch struct {
+	// Name of the query parameter or cookie
+	Name string `json:"name"`
+	// Match of its value
+	Value StringMatch `json:"value"`
+}
+
+// RouteService Definition of a service which is the target of a route
+type RouteService struct {
+	// Name of the service
+	Name *string `json:"name"`
+	// Namespace of the service, defaults to the namespace of the Gate
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+	// Port of the service to expose
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99999
+	Port *int32 `json:"port"`
+}
+
+// StringMatch Describes how to match a given string. Exactly one of the fields must be set.
+type StringMatch struct {
+	// Exact string match
+	Exact string `json:"exact,omitempty"`
+	// Prefix-based match
+	Prefix string `json:"prefix,omitempty"`
+	// Suffix-based match
+	Suffix string `json:"suffix,omitempty"`
+	// ECMAscript style regex-based match
+	Regex string `json:"regex,omitempty"`
 }
 
 type AuthStrategy struct {
-	// +kubebuilder:validation:Enum=JWT;OAUTH;PASSTHROUGH
+	// +kubebuilder:validation:Enum=JWT;OAUTH;PASSTHROUGH;MTLS;APIKEY
 	Name *string `json:"name"`
 	// Config configures the auth strategy. Configuration keys vary per strategy.
 	// +kubebuilder:validation:Type=object
 	Config *runtime.RawExtension `json:"config,omitempty"`
+	// Authenticators tried in sequence after the one of the strategy, until one of them authenticates the request,
+	// e.g. OAUTH for machine clients after JWT for browser clients. The chain is run by the access rules of the auth
+	// proxy, so only JWT and OAUTH can be chained, and exactly one OAUTH defines the paths of the access rules.
+	// +optional
+	Authenticators []Authenticator `json:"authenticators,omitempty"`
+}
+
+// Authenticator Authenticator of a chain, configured like the auth strategy of the same name
+type Authenticator struct {
+	// +kubebuilder:validation:Enum=JWT;OAUTH
+	Name *string `json:"name"`
+	// Config configures the authenticator. Configuration keys vary per strategy.
+	// +kubebuilder:validation:Type=object
+	Config *runtime.RawExtension `json:"config,omitempty"`
 }
 
 type GatewayResourceStatus struct {
@@ -0,0 +1,36 @@
+package v2alpha1
+
+// JWTModeConfig Config for JWT mode
+type JWTModeConfig struct {
+	// Issuer of the JWT
+	// +kubebuilder:validation:MinLength=1
+	Issuer string `json:"issuer"`
+	// URL of the provider's public key set used to validate the JWT signature
+	// +kubebuilder:validation:MinLength=1
+	JWKSURI string `json:"jwksUri"`
+	// Set of accepted JWT audiences
+	Audiences []string `json:"audiences,omitempty"`
+	// Array of paths with the claims and scopes they require. A request to several of the paths is accepted if its
+	// token grants the scopes and claims of one of them.
+	Paths []JWTOption `json:"paths,omitempty"`
+	// Rules selecting the paths on which the JWT is verified
+	TriggerRules []TriggerRule `json:"triggerRules,omitempty"`
+}
+
+// JWTOption Set of options for a path in the JWT mode
+type JWTOption struct {
+	// Path to be exposed, matched exactly, or by prefix with a trailing *. Chained with OAUTH, the path of the OAUTH
+	// authenticator the scopes apply to.
+	// +kubebuilder:validation:Pattern=^/([0-9a-zA-Z./*]+)
+	Path string `json:"path"`
+	// Set of JWT scopes the scope claim must list
+	Scopes []string `json:"scopes,omitempty"`
+	// Set of claims the JWT must contain with the given values. Not supported by JWT authenticators chained with OAUTH.
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty"`
+}
+
+// TriggerRule Set of paths excluded from the JWT verification
+type TriggerRule struct {
+	// Paths on which the JWT is not verified, matched exactly, by prefix or by suffix
+	ExcludedPaths []StringMatch `json:"excludedPaths,omitempty"`
+}
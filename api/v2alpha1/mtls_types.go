@@ -0,0 +1,27 @@
+package v2alpha1
+
+// MTLSModeConfig Config for MTLS mode, authenticating the clients by the certificates verified by the gateway.
+// The Gate must be exposed over TLS in MUTUAL mode with the secret named after the Gate.
+type MTLSModeConfig struct {
+	// Bundle of the CAs signing the accepted client certificates
+	CABundle *CABundleReference `json:"caBundle"`
+	// Regexes of the accepted client certificate subjects, e.g. CN=partner,O=Example.
+	// All the clients signed by the CAs are accepted if neither subjects nor SANs are set.
+	// +optional
+	Subjects []string `json:"subjects,omitempty"`
+	// Regexes of the accepted URI or DNS subject alternative names of the client certificates
+	// +optional
+	SubjectAltNames []string `json:"subjectAltNames,omitempty"`
+	// Request header forwarding the subject of the verified client certificate to the service, defaults to x-client-subject
+	// +optional
+	IdentityHeader string `json:"identityHeader,omitempty"`
+}
+
+// CABundleReference References the key of a secret in the namespace of the Gate holding PEM encoded CA certificates
+type CABundleReference struct {
+	// Name of the secret
+	SecretName string `json:"secretName"`
+	// Key of the CA certificates in the secret, defaults to ca.crt
+	// +optional
+	Key string `json:"key,omitempty"`
+}
@@ -17,4 +17,9 @@ type Option struct {
 	Scopes []string `json:"scopes,omitempty"`
 	// Set of allowed HTTP methods
 	Methods []string `json:"methods,omitempty"`
+	// Strategy overriding the one of the Gate on the path. PASSTHROUGH paths are public: they are routed straight to
+	// the service, bypassing the auth proxy. Defaults to OAUTH.
+	// +kubebuilder:validation:Enum=OAUTH;PASSTHROUGH
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
 }
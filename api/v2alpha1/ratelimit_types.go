@@ -0,0 +1,34 @@
+package v2alpha1
+
+// RateLimitUnit Unit of time the number of requests is limited per
+type RateLimitUnit string
+
+const (
+	RateLimitUnitSecond RateLimitUnit = "second"
+	RateLimitUnitMinute RateLimitUnit = "minute"
+	RateLimitUnitHour   RateLimitUnit = "hour"
+)
+
+// RateLimit Limits the number of requests the Gate accepts. The requests of all the clients are counted together by
+// each replica of the gateway.
+type RateLimit struct {
+	// Limits applied to the requests, the first limit matching a request applies
+	// +kubebuilder:validation:MinItems=1
+	Limits []RateLimitRule `json:"limits"`
+}
+
+// RateLimitRule Number of requests allowed per unit of time on the matching paths and methods
+type RateLimitRule struct {
+	// Path the limit applies to, all paths if not set
+	// +optional
+	Path *StringMatch `json:"path,omitempty"`
+	// HTTP methods the limit applies to, all methods if not set
+	// +optional
+	Methods []string `json:"methods,omitempty"`
+	// Number of requests allowed per unit
+	// +kubebuilder:validation:Minimum=1
+	Requests uint32 `json:"requests"`
+	// Unit of time the requests are counted in
+	// +kubebuilder:validation:Enum=second;minute;hour
+	Unit RateLimitUnit `json:"unit"`
+}
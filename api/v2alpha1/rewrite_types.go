@@ -0,0 +1,34 @@
+package v2alpha1
+
+// Rewrite Rewrites the requests before forwarding them to the service
+type Rewrite struct {
+	// Replaces the matched prefix of the path, or the whole path if it is not matched by prefix
+	// +optional
+	URI string `json:"uri,omitempty"`
+	// Replaces the Authority (Host) header
+	// +optional
+	Authority string `json:"authority,omitempty"`
+}
+
+// Headers Manipulates the headers of the requests and responses
+type Headers struct {
+	// Operations on the headers of the requests forwarded to the service
+	// +optional
+	Request *HeaderOperations `json:"request,omitempty"`
+	// Operations on the headers of the responses returned to the client
+	// +optional
+	Response *HeaderOperations `json:"response,omitempty"`
+}
+
+// HeaderOperations Headers to set, append or remove
+type HeaderOperations struct {
+	// Headers overwritten with the given values
+	// +optional
+	Set map[string]string `json:"set,omitempty"`
+	// Headers the given values are appended to
+	// +optional
+	Add map[string]string `json:"add,omitempty"`
+	// Headers removed
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+}
@@ -0,0 +1,39 @@
+package v2alpha1
+
+// TLSMode Describes how the gateway handles the TLS connections to the host of the Gate
+type TLSMode string
+
+const (
+	// TLSModeSimple terminates TLS at the gateway
+	TLSModeSimple TLSMode = "SIMPLE"
+	// TLSModeMutual terminates TLS at the gateway and verifies the client certificates
+	TLSModeMutual TLSMode = "MUTUAL"
+	// TLSModePassthrough forwards the encrypted connections to the service
+	TLSModePassthrough TLSMode = "PASSTHROUGH"
+)
+
+// TLSConfig Exposes the host of the Gate over TLS through a Gateway generated for the Gate
+type TLSConfig struct {
+	// TLS mode of the gateway server
+	// +kubebuilder:validation:Enum=SIMPLE;MUTUAL;PASSTHROUGH
+	Mode TLSMode `json:"mode"`
+	// Name of an existing secret holding the certificate in the namespace of the gateway workload, defaults to
+	// <namespace>-<name>-tls. Cannot be set along with an issuer, the requested certificate is stored in the default secret.
+	// The CA certificate verifying the clients in MUTUAL mode is read from the <secretName>-cacert secret, the MTLS
+	// strategy generates it next to the default secret.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// Issuer requested to sign the certificate stored in the secret
+	// +optional
+	Issuer *CertificateIssuer `json:"issuer,omitempty"`
+}
+
+// CertificateIssuer References a cert-manager issuer
+type CertificateIssuer struct {
+	// Name of the issuer
+	Name string `json:"name"`
+	// Kind of the issuer, Issuer or ClusterIssuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
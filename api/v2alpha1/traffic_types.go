@@ -0,0 +1,58 @@
+package v2alpha1
+
+// TrafficPolicy Timeout, retries and fault injection applied to the requests forwarded to a service
+type TrafficPolicy struct {
+	// Timeout of the requests, e.g. 10s
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+	// Retries of the failed requests
+	// +optional
+	Retries *Retries `json:"retries,omitempty"`
+	// Faults injected into the requests for testing the resilience of the clients
+	// +optional
+	Fault *FaultInjection `json:"fault,omitempty"`
+}
+
+// Retries Describes how failed requests are retried
+type Retries struct {
+	// Number of retries of a request
+	// +kubebuilder:validation:Minimum=1
+	Attempts int32 `json:"attempts"`
+	// Timeout of every attempt, e.g. 2s
+	PerTryTimeout string `json:"perTryTimeout"`
+	// Comma-separated conditions triggering a retry, e.g. 5xx,connect-failure
+	// +optional
+	RetryOn string `json:"retryOn,omitempty"`
+}
+
+// FaultInjection Delays or aborts a percentage of the requests
+type FaultInjection struct {
+	// Delay of the requests
+	// +optional
+	Delay *FaultDelay `json:"delay,omitempty"`
+	// Abort of the requests
+	// +optional
+	Abort *FaultAbort `json:"abort,omitempty"`
+}
+
+// FaultDelay Delays a percentage of the requests before forwarding them
+type FaultDelay struct {
+	// Percentage of the delayed requests
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+	// Delay of every affected request, e.g. 5s
+	FixedDelay string `json:"fixedDelay"`
+}
+
+// FaultAbort Responds to a percentage of the requests with an error instead of forwarding them
+type FaultAbort struct {
+	// Percentage of the aborted requests
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+	// HTTP status code returned for the aborted requests
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	HTTPStatus int32 `json:"httpStatus"`
+}
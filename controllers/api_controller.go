@@ -17,27 +17,61 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"github.com/kyma-incubator/api-gateway/internal/metrics"
 	"github.com/kyma-incubator/api-gateway/internal/processing"
 	"time"
 
 	"github.com/go-logr/logr"
 	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	securityv1beta1 "github.com/kyma-incubator/api-gateway/internal/types/istio/security/v1beta1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
 	"github.com/kyma-incubator/api-gateway/internal/validation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// gateFinalizer guards the removal of the resources generated for a Gate
+const gateFinalizer = "gateway.kyma-project.io/subresources"
+
+// Results of the reconciles counted in the metrics
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+	resultRetry   = "retry"
 )
 
 // ApiReconciler reconciles a Api object
 type ApiReconciler struct {
 	client.Client
-	Log logr.Logger
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// Namespace of the certificates issued for the Gates exposed over TLS, which is the namespace of the ingress gateway
+	CertificateNamespace string
 }
 
-// +kubebuilder:rbac:groups=gateway.kyma-project.io,resources=apis,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=gateway.kyma-project.io,resources=apis/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.kyma-project.io,resources=gates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.kyma-project.io,resources=gates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.istio.io,resources=virtualservices;serviceentries;destinationrules;envoyfilters;gateways,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=security.istio.io,resources=requestauthentications;authorizationpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=certmanager.k8s.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=oathkeeper.ory.sh,resources=rules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// The Services are read for the selector of the pods verifying the tokens of the JWT strategy
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+// The Secrets are only read in all the namespaces: the CA bundles of the MTLS Gates, and the API key Secrets which are
+// listed and watched by their label. The Secrets holding the CA bundles are written in the certificate namespace only,
+// with the Role of config/rbac/certificate_namespace_role.yaml.
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 func (r *ApiReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -47,11 +81,30 @@ func (r *ApiReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	err := r.Get(ctx, req.NamespacedName, api)
 	if err != nil {
-		if !apierrs.IsNotFound(err) {
-			return reconcile.Result{}, err
+		if apierrs.IsNotFound(err) {
+			// The Gate is gone, its generated resources were removed by the finalizer
+			return ctrl.Result{}, nil
 		}
+		return reconcile.Result{}, err
 	}
 
+	if !api.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, api)
+	}
+
+	if !containsString(api.ObjectMeta.Finalizers, gateFinalizer) {
+		api.ObjectMeta.Finalizers = append(api.ObjectMeta.Finalizers, gateFinalizer)
+		err = r.Update(ctx, api)
+		if err != nil {
+			return reconcile.Result{Requeue: true}, err
+		}
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.ReconcileDuration.WithLabelValues(strategyLabel(api)).Observe(time.Since(start).Seconds())
+	}()
+
 	APIStatus := &gatewayv2alpha1.GatewayResourceStatus{
 		Code: gatewayv2alpha1.STATUS_OK,
 	}
@@ -70,64 +123,109 @@ func (r *ApiReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		Description: "Skipped setting Oathkeeper Access Rule",
 	}
 
-	if api.Generation != api.Status.ObservedGeneration {
-		r.Log.Info("Api processing")
+	rateLimitStatus := &gatewayv2alpha1.GatewayResourceStatus{
+		Code:        gatewayv2alpha1.STATUS_SKIPPED,
+		Description: "Skipped setting Istio Envoy Filter",
+	}
+	tlsStatus := &gatewayv2alpha1.GatewayResourceStatus{
+		Code:        gatewayv2alpha1.STATUS_SKIPPED,
+		Description: "Skipped setting Istio Gateway",
+	}
 
-		validationStrategy, err := validation.NewFactory(r.Log).StrategyFor(*api.Spec.Auth.Name)
-		if err != nil {
-			_, updateStatErr := r.updateStatus(ctx, api, generateErrorStatus(err), virtualServiceStatus, policyStatus, accessRuleStatus)
-			if updateStatErr != nil {
-				return reconcile.Result{Requeue: true}, err
-			}
-			return ctrl.Result{}, err
-		}
+	// The generated resources are compared with the desired ones on every reconcile, so that their drift is restored
+	r.Log.Info("Api processing")
 
-		err = validationStrategy.Validate(api.Spec.Auth.Config)
-		if err != nil {
-			_, updateStatErr := r.updateStatus(ctx, api, generateErrorStatus(err), virtualServiceStatus, policyStatus, accessRuleStatus)
-			if updateStatErr != nil {
-				return reconcile.Result{Requeue: true}, err
-			}
-			return ctrl.Result{}, err
+	err = validation.NewFactory(r.Log).ValidateGate(api)
+	if err != nil {
+		return r.handleError(ctx, api, reasonValidationFailed, permanent(err), virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	}
+
+	// The VirtualService of a Gate whose host is claimed by an earlier Gate is left untouched. The claim is retried,
+	// as the earlier Gate may release the host.
+	err = validation.ValidateHostClaim(ctx, r.Client, api)
+	if err != nil {
+		return r.handleError(ctx, api, reasonHostConflict, err, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	}
+
+	// A chain of authenticators is run by the access rules of the OAUTH strategy, whatever strategy it starts with
+	strategyName := *api.Spec.Auth.Name
+	if len(api.Spec.Auth.Authenticators) > 0 {
+		strategyName = gatewayv2alpha1.OAUTH
+	}
+
+	processingStrategy, err := processing.NewFactory(r.Client, r.Log, r.Recorder).StrategyFor(strategyName)
+	if err != nil {
+		return r.handleError(ctx, api, reasonValidationFailed, permanent(err), virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	}
+
+	err = processingStrategy.Process(ctx, api)
+	if err != nil {
+		virtualServiceStatus = generateErrorStatus(err)
+		switch strategyName {
+		case gatewayv2alpha1.OAUTH:
+			accessRuleStatus = generateErrorStatus(err)
+		case gatewayv2alpha1.JWT:
+			policyStatus = generateErrorStatus(err)
 		}
 
-		processingStrategy, err := processing.NewFactory(r.Client, r.Log).StrategyFor(*api.Spec.Auth.Name)
-		if err != nil {
-			_, updateStatErr := r.updateStatus(ctx, api, generateErrorStatus(err), virtualServiceStatus, policyStatus, accessRuleStatus)
-			if updateStatErr != nil {
-				return reconcile.Result{Requeue: true}, err
-			}
-			return ctrl.Result{}, err
+		return r.handleError(ctx, api, reasonProcessingFailed, err, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	}
+
+	virtualServiceStatus = &gatewayv2alpha1.GatewayResourceStatus{
+		Code: gatewayv2alpha1.STATUS_OK,
+	}
+	switch strategyName {
+	case gatewayv2alpha1.OAUTH:
+		accessRuleStatus = &gatewayv2alpha1.GatewayResourceStatus{
+			Code: gatewayv2alpha1.STATUS_OK,
+		}
+	case gatewayv2alpha1.JWT:
+		policyStatus = &gatewayv2alpha1.GatewayResourceStatus{
+			Code: gatewayv2alpha1.STATUS_OK,
 		}
+	}
 
-		err = processingStrategy.Process(ctx, api)
-		if err != nil {
-			virtualServiceStatus := &gatewayv2alpha1.GatewayResourceStatus{
-				Code:        gatewayv2alpha1.STATUS_ERROR,
-				Description: err.Error(),
-			}
-
-			_, updateStatErr := r.updateStatus(ctx, api, generateErrorStatus(err), virtualServiceStatus, policyStatus, accessRuleStatus)
-			if updateStatErr != nil {
-				return reconcile.Result{Requeue: true}, err
-			}
-			return ctrl.Result{}, err
+	err = processing.NewRateLimiter(r.Client, r.Log, r.Recorder).Process(ctx, api)
+	if err != nil {
+		rateLimitStatus = generateErrorStatus(err)
+		return r.handleError(ctx, api, reasonProcessingFailed, err, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	}
+	if api.Spec.RateLimit != nil {
+		rateLimitStatus = &gatewayv2alpha1.GatewayResourceStatus{
+			Code: gatewayv2alpha1.STATUS_OK,
 		}
+	}
 
-		virtualServiceStatus := &gatewayv2alpha1.GatewayResourceStatus{
+	err = processing.NewTLSProcessor(r.Client, r.Log, r.Recorder, r.CertificateNamespace).Process(ctx, api)
+	if err != nil {
+		tlsStatus = generateErrorStatus(err)
+		return r.handleError(ctx, api, reasonProcessingFailed, err, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	}
+	if api.Spec.TLS != nil {
+		tlsStatus = &gatewayv2alpha1.GatewayResourceStatus{
 			Code: gatewayv2alpha1.STATUS_OK,
 		}
+	}
+
+	cleanupResult, err := processing.NewCleaner(r.Client, r.Log, r.Recorder, r.CertificateNamespace).DeleteOutdated(ctx, api)
+	if err != nil {
+		return r.handleError(ctx, api, reasonProcessingFailed, err, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	}
+	reportDeleted(policyStatus, cleanupResult.Policies, "Istio Policy")
+	reportDeleted(accessRuleStatus, cleanupResult.AccessRules, "Oathkeeper Access Rule")
+	reportDeleted(rateLimitStatus, cleanupResult.EnvoyFilters, "Istio Envoy Filter")
+	reportDeleted(tlsStatus, cleanupResult.Gateways, "Istio Gateway")
 
-		_, err = r.updateStatus(ctx, api, APIStatus, virtualServiceStatus, policyStatus, accessRuleStatus)
+	metrics.ReconcileTotal.WithLabelValues(strategyLabel(api), resultSuccess).Inc()
+	_, err = r.updateStatus(ctx, api, 0, reasonReady, APIStatus, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
 
-		if err != nil {
-			return reconcile.Result{Requeue: true}, err
-		}
+	if err != nil {
+		return reconcile.Result{Requeue: true}, err
 	}
 
 	// demo sample fetching virtualservices
 
-	//list := networkingv1alpha3.VirtualServiceList{}
+	//list := istiov1alpha3.VirtualServiceList{}
 	//err = r.Client.List(context.TODO(), &list, client.InNamespace(req.Namespace))
 	//if err != nil {
 	//	fmt.Printf("ooops, error occured when fetching vs " + err.Error())
@@ -139,19 +237,111 @@ func (r *ApiReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	return ctrl.Result{}, nil
 }
 
+// finalize removes the resources generated for a deleted Gate and releases its finalizer
+func (r *ApiReconciler) finalize(ctx context.Context, api *gatewayv2alpha1.Gate) (ctrl.Result, error) {
+	if !containsString(api.ObjectMeta.Finalizers, gateFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	r.Log.Info("Removing resources generated for deleted Gate", "name", api.ObjectMeta.Name, "namespace", api.ObjectMeta.Namespace)
+	_, err := processing.NewCleaner(r.Client, r.Log, r.Recorder, r.CertificateNamespace).DeleteAll(ctx, api)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	api.ObjectMeta.Finalizers = removeString(api.ObjectMeta.Finalizers, gateFinalizer)
+	err = r.Update(ctx, api)
+	if err != nil {
+		return reconcile.Result{Requeue: true}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// handleError reports the error in the status and the events of the Gate. Permanent errors are not retried until the Gate changes,
+// transient ones are retried with an exponential backoff counted in the status.
+func (r *ApiReconciler) handleError(ctx context.Context, api *gatewayv2alpha1.Gate, reason string, err error, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus *gatewayv2alpha1.GatewayResourceStatus) (ctrl.Result, error) {
+	r.Recorder.Event(api, corev1.EventTypeWarning, reason, err.Error())
+	if reason != reasonProcessingFailed {
+		metrics.ValidationFailuresTotal.Inc()
+	}
+
+	var retryCount int32
+	if !isPermanent(err) {
+		retryCount = api.Status.RetryCount + 1
+	}
+
+	_, updateStatErr := r.updateStatus(ctx, api, retryCount, reason, generateErrorStatus(err), virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus)
+	if updateStatErr != nil {
+		return reconcile.Result{Requeue: true}, updateStatErr
+	}
+
+	if isPermanent(err) {
+		metrics.ReconcileTotal.WithLabelValues(strategyLabel(api), resultError).Inc()
+		r.Log.Info("Gate cannot be processed until it is changed", "name", api.Name, "namespace", api.Namespace, "error", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	metrics.ReconcileTotal.WithLabelValues(strategyLabel(api), resultRetry).Inc()
+	delay := retryDelay(api.Status.RetryCount)
+	r.Log.Info("Retrying Gate processing", "name", api.Name, "namespace", api.Namespace, "retry", api.Status.RetryCount, "after", delay.String(), "error", err.Error())
+	return ctrl.Result{RequeueAfter: delay}, nil
+}
+
 func (r *ApiReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	apiKeySecrets, err := apiKeySecretsInformer(mgr)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayv2alpha1.Gate{}).
+		Owns(&istiov1alpha3.VirtualService{}).
+		Owns(&istiov1alpha3.DestinationRule{}).
+		Owns(&istiov1alpha3.ServiceEntry{}).
+		Owns(&rulev1alpha1.Rule{}).
+		Owns(&securityv1beta1.RequestAuthentication{}).
+		Owns(&securityv1beta1.AuthorizationPolicy{}).
+		Watches(&source.Kind{Type: &istiov1alpha3.EnvoyFilter{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: gateForGenerated()}).
+		Owns(&istiov1alpha3.Gateway{}).
+		Watches(&source.Informer{Informer: apiKeySecrets}, &handler.EnqueueRequestsFromMapFunc{ToRequests: r.gatesForSecret()}).
+		WithEventFilter(ignoreStatusUpdates()).
 		Complete(r)
 }
 
-func (r *ApiReconciler) updateStatus(ctx context.Context, api *gatewayv2alpha1.Gate, APIStatus, virtualServiceStatus, policyStatus, accessRuleStatus *gatewayv2alpha1.GatewayResourceStatus) (*gatewayv2alpha1.Gate, error) {
+// gateForGenerated maps a generated resource to its Gate, which the EnvoyFilters in the namespace of the gateway
+// cannot be owned by. The resources no event is watched for, e.g. the Certificates in the certificate namespace, are
+// corrected by the periodic resync of the Gates.
+func gateForGenerated() handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		gate, ok := processing.GeneratingGate(obj.Meta)
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: gate}}
+	}
+}
+
+// updateStatus records the statuses of the generated resources and the conditions following from the reason the
+// reconcile ended with, reasonReady when it succeeded
+func (r *ApiReconciler) updateStatus(ctx context.Context, api *gatewayv2alpha1.Gate, retryCount int32, reason string, APIStatus, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus *gatewayv2alpha1.GatewayResourceStatus) (*gatewayv2alpha1.Gate, error) {
+	now := time.Now()
+	previous := api.Status.DeepCopy()
+
 	api.Status.ObservedGeneration = api.Generation
-	api.Status.LastProcessedTime = &v1.Time{Time: time.Now()}
+	api.Status.RetryCount = retryCount
 	api.Status.GateStatus = APIStatus
 	api.Status.VirtualServiceStatus = virtualServiceStatus
 	api.Status.PolicyServiceStatus = policyStatus
 	api.Status.AccessRuleStatus = accessRuleStatus
+	api.Status.RateLimitStatus = rateLimitStatus
+	api.Status.TLSStatus = tlsStatus
+	updateConditions(api, reason, APIStatus, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus, now)
+
+	// The Gate is reconciled on every change of its status, so an unchanged status is not written again
+	if equality.Semantic.DeepEqual(previous, &api.Status) {
+		return api, nil
+	}
+	api.Status.LastProcessedTime = &v1.Time{Time: now}
 
 	err := r.Status().Update(ctx, api)
 	if err != nil {
@@ -160,9 +350,44 @@ func (r *ApiReconciler) updateStatus(ctx context.Context, api *gatewayv2alpha1.G
 	return api, nil
 }
 
+// strategyLabel returns the auth strategy of the Gate for labeling its metrics
+func strategyLabel(api *gatewayv2alpha1.Gate) string {
+	if api.Spec.Auth == nil || api.Spec.Auth.Name == nil {
+		return ""
+	}
+	return *api.Spec.Auth.Name
+}
+
 func generateErrorStatus(err error) *gatewayv2alpha1.GatewayResourceStatus {
 	return &gatewayv2alpha1.GatewayResourceStatus{
 		Code:        gatewayv2alpha1.STATUS_ERROR,
 		Description: err.Error(),
 	}
 }
+
+// reportDeleted notes the deleted outdated resources in the status of a resource kind no longer used by the Gate
+func reportDeleted(status *gatewayv2alpha1.GatewayResourceStatus, deleted int, kind string) {
+	if deleted == 0 || status.Code != gatewayv2alpha1.STATUS_SKIPPED {
+		return
+	}
+	status.Description = fmt.Sprintf("Deleted %d outdated %s(s)", deleted, kind)
+}
+
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(slice []string, s string) []string {
+	var result []string
+	for _, item := range slice {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -2,11 +2,20 @@ package controllers_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"time"
 
 	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
 	"github.com/kyma-incubator/api-gateway/controllers"
+	certmanagerv1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/certmanager/v1alpha1"
+	securityv1beta1 "github.com/kyma-incubator/api-gateway/internal/types/istio/security/v1beta1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -14,7 +23,6 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
-	networkingv1alpha3 "knative.dev/pkg/apis/istio/v1alpha3"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +59,724 @@ var _ = Describe("Controller", func() {
 				Expect(res.Status.PolicyServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_SKIPPED))
 				Expect(res.Status.VirtualServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
 				Expect(res.Status.GateStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+
+				Expect(findCondition(res, gatewayv2alpha1.ConditionReady).Status).To(Equal(gatewayv2alpha1.ConditionTrue))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionValidated).Status).To(Equal(gatewayv2alpha1.ConditionTrue))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionVirtualServiceReady).Status).To(Equal(gatewayv2alpha1.ConditionTrue))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionAccessRulesReady).Reason).To(Equal("NotRequired"))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionPolicyReady).ObservedGeneration).To(Equal(testAPI.Generation))
+			})
+
+			It("should create access rules in OAUTH mode", func() {
+				testAPI := fixOauthAPI()
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.AccessRuleStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(res.Status.PolicyServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_SKIPPED))
+				Expect(res.Status.VirtualServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(res.Status.GateStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+
+				rules := rulev1alpha1.RuleList{}
+				err = ts.mgr.GetClient().List(context.Background(), &rules)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rules.Items).To(HaveLen(2))
+			})
+
+			It("should route the public paths of the OAUTH mode around the auth proxy", func() {
+				testAPI := fixOauthAPI()
+				testAPI.Spec.Auth.Config = &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/healthz","strategy":"PASSTHROUGH"},{"path":"/foo","scopes":["read"],"methods":["GET"]}]}`)}
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				rules := rulev1alpha1.RuleList{}
+				err = ts.mgr.GetClient().List(context.Background(), &rules)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rules.Items).To(HaveLen(1))
+				Expect(rules.Items[0].Spec.Match.URL).To(Equal("<http|https>://foo.bar</foo>"))
+
+				vs := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-test"}, &vs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vs.Spec.HTTP).To(HaveLen(2))
+				Expect(vs.Spec.HTTP[0].Match[0].URI.Regex).To(Equal("/healthz"))
+				Expect(vs.Spec.HTTP[0].Route[0].Destination.Host).To(Equal("test..svc.cluster.local"))
+				Expect(vs.Spec.HTTP[1].Route[0].Destination.Host).To(Equal("ory-oathkeeper-proxy.kyma-system.svc.cluster.local"))
+			})
+
+			It("should chain the JWT and OAUTH authenticators in the access rules", func() {
+				testAPI := fixJWTAPI()
+				oauth := gatewayv2alpha1.OAUTH
+				testAPI.Spec.Auth.Authenticators = []gatewayv2alpha1.Authenticator{
+					{Name: &oauth, Config: &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/foo","scopes":["read"],"methods":["GET"]}]}`)}},
+				}
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.AccessRuleStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(res.Status.PolicyServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_SKIPPED))
+				Expect(res.Status.GateStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+
+				rules := rulev1alpha1.RuleList{}
+				err = ts.mgr.GetClient().List(context.Background(), &rules)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rules.Items).To(HaveLen(1))
+				Expect(rules.Items[0].Spec.Authenticators).To(HaveLen(2))
+				Expect(rules.Items[0].Spec.Authenticators[0].Name).To(Equal("jwt"))
+				Expect(rules.Items[0].Spec.Authenticators[1].Name).To(Equal("oauth2_introspection"))
+			})
+
+			It("should delete access rules after switching from OAUTH to PASSTHROUGH", func() {
+				testAPI := fixOauthAPI()
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+
+				passthrough := gatewayv2alpha1.PASSTHROUGH
+				res.Spec.Auth = &gatewayv2alpha1.AuthStrategy{Name: &passthrough}
+				res.Generation = 2
+				err = ts.mgr.GetClient().Update(context.Background(), &res)
+				Expect(err).ToNot(HaveOccurred())
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.AccessRuleStatus.Code).To(Equal(gatewayv2alpha1.STATUS_SKIPPED))
+				Expect(res.Status.AccessRuleStatus.Description).To(Equal("Deleted 2 outdated Oathkeeper Access Rule(s)"))
+				Expect(res.Status.VirtualServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+
+				rules := rulev1alpha1.RuleList{}
+				err = ts.mgr.GetClient().List(context.Background(), &rules)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rules.Items).To(BeEmpty())
+
+				virtualServices := istiov1alpha3.VirtualServiceList{}
+				err = ts.mgr.GetClient().List(context.Background(), &virtualServices)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(virtualServices.Items).To(HaveLen(1))
+			})
+
+			It("should limit the rate in the namespace of the gateway", func() {
+				testAPI := fixAPI()
+				testAPI.Spec.RateLimit = &gatewayv2alpha1.RateLimit{
+					Limits: []gatewayv2alpha1.RateLimitRule{{Requests: 10, Unit: gatewayv2alpha1.RateLimitUnitSecond}},
+				}
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.RateLimitStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionRateLimitReady).Status).To(Equal(gatewayv2alpha1.ConditionTrue))
+
+				envoyFilters := istiov1alpha3.EnvoyFilterList{}
+				err = ts.mgr.GetClient().List(context.Background(), &envoyFilters)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(envoyFilters.Items).To(HaveLen(1))
+				Expect(envoyFilters.Items[0].Namespace).To(Equal("some-namespace"))
+
+				res.Spec.RateLimit = nil
+				res.Generation = 2
+				err = ts.mgr.GetClient().Update(context.Background(), &res)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.RateLimitStatus.Code).To(Equal(gatewayv2alpha1.STATUS_SKIPPED))
+				Expect(res.Status.RateLimitStatus.Description).To(Equal("Deleted 1 outdated Istio Envoy Filter(s)"))
+
+				err = ts.mgr.GetClient().List(context.Background(), &envoyFilters)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(envoyFilters.Items).To(BeEmpty())
+			})
+
+			It("should expose the Gate over TLS with a generated Gateway and certificate", func() {
+				testAPI := fixAPI()
+				testAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{
+					Mode:   gatewayv2alpha1.TLSModeSimple,
+					Issuer: &gatewayv2alpha1.CertificateIssuer{Name: "letsencrypt", Kind: "ClusterIssuer"},
+				}
+				ingressGateway := &istiov1alpha3.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Name: "some-gateway", Namespace: "some-namespace"},
+					Spec:       istiov1alpha3.GatewaySpec{Selector: map[string]string{"istio": "ingressgateway"}},
+				}
+
+				ts = getTestSuite(testAPI, ingressGateway)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.TLSStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionTLSReady).Status).To(Equal(gatewayv2alpha1.ConditionTrue))
+
+				gateway := istiov1alpha3.Gateway{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-test"}, &gateway)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(gateway.Spec.Selector).To(Equal(ingressGateway.Spec.Selector))
+				Expect(gateway.Spec.Servers[0].Hosts).To(ConsistOf(host))
+
+				vs := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-test"}, &vs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vs.Spec.Gateways).To(ConsistOf(gateway.Name))
+
+				certificates := certmanagerv1alpha1.CertificateList{}
+				err = ts.mgr.GetClient().List(context.Background(), &certificates)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(certificates.Items).To(HaveLen(1))
+				Expect(certificates.Items[0].Namespace).To(Equal("istio-system"))
+				Expect(certificates.Items[0].Spec.IssuerRef.Kind).To(Equal("ClusterIssuer"))
+
+				res.Spec.TLS = nil
+				res.Generation = 2
+				err = ts.mgr.GetClient().Update(context.Background(), &res)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.TLSStatus.Code).To(Equal(gatewayv2alpha1.STATUS_SKIPPED))
+				Expect(res.Status.TLSStatus.Description).To(Equal("Deleted 1 outdated Istio Gateway(s)"))
+
+				err = ts.mgr.GetClient().List(context.Background(), &certificates)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(certificates.Items).To(BeEmpty())
+			})
+
+			It("should not take over a Certificate which was not generated for the Gate", func() {
+				testAPI := fixAPI()
+				testAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{
+					Mode:   gatewayv2alpha1.TLSModeSimple,
+					Issuer: &gatewayv2alpha1.CertificateIssuer{Name: "letsencrypt"},
+				}
+				ingressGateway := &istiov1alpha3.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Name: "some-gateway", Namespace: "some-namespace"},
+					Spec:       istiov1alpha3.GatewaySpec{Selector: map[string]string{"istio": "ingressgateway"}},
+				}
+				platformCertificate := &certmanagerv1alpha1.Certificate{
+					ObjectMeta: metav1.ObjectMeta{Name: testAPI.Namespace + "-" + testAPI.Name + "-tls", Namespace: "istio-system"},
+					Spec:       certmanagerv1alpha1.CertificateSpec{CommonName: "platform.example.com"},
+				}
+
+				ts = getTestSuite(testAPI, ingressGateway, platformCertificate)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeZero())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.TLSStatus.Code).To(Equal(gatewayv2alpha1.STATUS_ERROR))
+				Expect(res.Status.TLSStatus.Description).To(ContainSubstring("was not generated for the Gate"))
+
+				certificate := certmanagerv1alpha1.Certificate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: "istio-system", Name: platformCertificate.Name}, &certificate)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(certificate.Spec.CommonName).To(Equal("platform.example.com"))
+			})
+
+			It("should authenticate the clients with certificates in MTLS mode", func() {
+				testAPI := fixAPI()
+				mtlsStrategy := gatewayv2alpha1.MTLS
+				testAPI.Spec.Auth = &gatewayv2alpha1.AuthStrategy{
+					Name:   &mtlsStrategy,
+					Config: &runtime.RawExtension{Raw: []byte(`{"caBundle":{"secretName":"partner-ca"},"subjects":["CN=partner"]}`)},
+				}
+				testAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeMutual}
+				ingressGateway := &istiov1alpha3.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Name: "some-gateway", Namespace: "some-namespace"},
+					Spec:       istiov1alpha3.GatewaySpec{Selector: map[string]string{"istio": "ingressgateway"}},
+				}
+				caBundle := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "partner-ca", Namespace: testAPI.Namespace},
+					Data:       map[string][]byte{"ca.crt": []byte("partner CA")},
+				}
+
+				ts = getTestSuite(testAPI, ingressGateway, caBundle)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.VirtualServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(res.Status.TLSStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+
+				gateway := istiov1alpha3.Gateway{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-test"}, &gateway)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(gateway.Spec.Servers[0].TLS.Mode).To(Equal(istiov1alpha3.TLSModeMutual))
+
+				caSecret := corev1.Secret{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: "istio-system", Name: gateway.Spec.Servers[0].TLS.CredentialName + "-cacert"}, &caSecret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caSecret.Data["cacert"]).To(Equal([]byte("partner CA")))
+
+				vs := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-test"}, &vs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vs.Spec.HTTP[0].Match[0].Headers).To(HaveKey("x-forwarded-client-cert"))
+				Expect(vs.Spec.HTTP[0].Headers.Request.Set).To(HaveKeyWithValue("x-client-subject", "%DOWNSTREAM_PEER_SUBJECT%"))
+			})
+
+			It("should reload the keys of the APIKEY strategy from the selected Secrets", func() {
+				testAPI := fixAPI()
+				apiKeyStrategy := gatewayv2alpha1.APIKEY
+				testAPI.Spec.Auth = &gatewayv2alpha1.AuthStrategy{
+					Name:   &apiKeyStrategy,
+					Config: &runtime.RawExtension{Raw: []byte(`{"secretSelector":{"matchLabels":{"app":"tooling"}}}`)},
+				}
+				keySecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "tooling-key", Namespace: testAPI.Namespace, Labels: map[string]string{"app": "tooling", gatewayv2alpha1.APIKeySecretLabel: "tooling"}},
+					Data:       map[string][]byte{"api-key": []byte("first-key")},
+				}
+				otherSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "other-key", Namespace: testAPI.Namespace, Labels: map[string]string{"app": "tooling"}},
+					Data:       map[string][]byte{"api-key": []byte("other-key")},
+				}
+
+				ts = getTestSuite(testAPI, keySecret, otherSecret)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				envoyFilters := istiov1alpha3.EnvoyFilterList{}
+				err = ts.mgr.GetClient().List(context.Background(), &envoyFilters)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(envoyFilters.Items).To(HaveLen(1))
+				Expect(envoyFilters.Items[0].Namespace).To(Equal("some-namespace"))
+				Expect(string(envoyFilters.Items[0].Spec.ConfigPatches[0].Patch.Value.Raw)).To(ContainSubstring(keyDigest("first-key")))
+				Expect(string(envoyFilters.Items[0].Spec.ConfigPatches[0].Patch.Value.Raw)).ToNot(ContainSubstring(keyDigest("other-key")))
+				Expect(string(envoyFilters.Items[0].Spec.ConfigPatches[0].Patch.Value.Raw)).ToNot(ContainSubstring("first-key"))
+
+				keySecret.Data["api-key"] = []byte("second-key")
+				err = ts.mgr.GetClient().Update(context.Background(), keySecret)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				err = ts.mgr.GetClient().List(context.Background(), &envoyFilters)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(envoyFilters.Items[0].Spec.ConfigPatches[0].Patch.Value.Raw)).To(ContainSubstring(keyDigest("second-key")))
+				Expect(string(envoyFilters.Items[0].Spec.ConfigPatches[0].Patch.Value.Raw)).ToNot(ContainSubstring(keyDigest("first-key")))
+			})
+
+			It("should add the finalizer", func() {
+				testAPI := fixAPI()
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.ObjectMeta.Finalizers).To(ContainElement("gateway.kyma-project.io/subresources"))
+			})
+
+			It("should remove generated resources of a deleted Gate", func() {
+				testAPI := fixOauthAPI()
+				gateLabels := map[string]string{"gateway.kyma-project.io/gate-name": testAPI.Name, "gateway.kyma-project.io/gate-namespace": testAPI.Namespace}
+				caSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "istio-system", Labels: gateLabels}}
+				// Secrets are only deleted in the certificate namespace, the labels of the Secrets elsewhere are not trusted
+				tenantSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: testAPI.Namespace, Labels: gateLabels}}
+
+				ts = getTestSuite(testAPI, caSecret, tenantSecret)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+
+				now := metav1.Now()
+				res.ObjectMeta.DeletionTimestamp = &now
+				err = ts.mgr.GetClient().Update(context.Background(), &res)
+				Expect(err).ToNot(HaveOccurred())
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				rules := rulev1alpha1.RuleList{}
+				err = ts.mgr.GetClient().List(context.Background(), &rules)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rules.Items).To(BeEmpty())
+
+				virtualServices := istiov1alpha3.VirtualServiceList{}
+				err = ts.mgr.GetClient().List(context.Background(), &virtualServices)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(virtualServices.Items).To(BeEmpty())
+
+				secrets := corev1.SecretList{}
+				err = ts.mgr.GetClient().List(context.Background(), &secrets)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(secrets.Items).To(HaveLen(1))
+				Expect(secrets.Items[0].Name).To(Equal("tenant"))
+
+				deleted := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &deleted)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deleted.ObjectMeta.Finalizers).To(BeEmpty())
+			})
+
+			It("should finalize a Gate on a cluster without cert-manager", func() {
+				testAPI := fixAPI()
+				testAPI.ObjectMeta.Finalizers = []string{"gateway.kyma-project.io/subresources"}
+				now := metav1.Now()
+				testAPI.ObjectMeta.DeletionTimestamp = &now
+
+				ts = getTestSuite(testAPI)
+				ts.mgr = getFakeManager(&missingKindClient{Client: ts.mgr.GetClient(), missing: &certmanagerv1alpha1.CertificateList{}}, scheme.Scheme)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				deleted := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &deleted)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deleted.ObjectMeta.Finalizers).To(BeEmpty())
+			})
+
+			It("should ignore a Gate which no longer exists", func() {
+				ts = getTestSuite()
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "missing"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+			})
+
+			It("should split the traffic between the subsets of the service", func() {
+				testAPI := fixAPI()
+				testAPI.Spec.Backends = []gatewayv2alpha1.Backend{
+					{Subset: "v1", Labels: map[string]string{"version": "v1"}, Weight: 90},
+					{Subset: "v2", Labels: map[string]string{"version": "v2"}, Weight: 10},
+				}
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				destinationRule := istiov1alpha3.DestinationRule{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + serviceName + "-subsets"}, &destinationRule)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(destinationRule.Spec.Subsets).To(HaveLen(2))
+
+				vs := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + serviceName}, &vs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vs.Spec.HTTP[0].Route).To(HaveLen(2))
+				Expect(vs.Spec.HTTP[0].Route[0].Destination.Subset).To(Equal("v1"))
+				Expect(vs.Spec.HTTP[0].Route[0].Weight).To(Equal(90))
+			})
+
+			It("should register an external service", func() {
+				testAPI := fixAPI()
+				externalName := "api.imgur.com"
+				isExternal := true
+				originateTLS := true
+				testAPI.Spec.Service.Name = &externalName
+				testAPI.Spec.Service.IsExternal = &isExternal
+				testAPI.Spec.Service.OriginateTLS = &originateTLS
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				serviceEntry := istiov1alpha3.ServiceEntry{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + externalName}, &serviceEntry)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(serviceEntry.Spec.Hosts).To(ConsistOf(externalName))
+
+				destinationRule := istiov1alpha3.DestinationRule{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + externalName}, &destinationRule)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(destinationRule.Spec.Host).To(Equal(externalName))
+
+				vs := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + externalName}, &vs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vs.Spec.HTTP[0].Route[0].Destination.Host).To(Equal(externalName))
+			})
+
+			It("should not expose a host already claimed by another Gate", func() {
+				firstAPI := fixAPI()
+				firstAPI.ObjectMeta.Name = "first"
+				firstAPI.ObjectMeta.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+				testAPI := fixAPI()
+				testAPI.ObjectMeta.CreationTimestamp = metav1.Now()
+
+				ts = getTestSuite(firstAPI, testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.GateStatus.Code).To(Equal(gatewayv2alpha1.STATUS_ERROR))
+				Expect(res.Status.GateStatus.Description).To(ContainSubstring("already claimed by Gate /first"))
+				Expect(res.Status.RetryCount).To(Equal(int32(1)))
+
+				Expect(findCondition(res, gatewayv2alpha1.ConditionValidated).Status).To(Equal(gatewayv2alpha1.ConditionTrue))
+				ready := findCondition(res, gatewayv2alpha1.ConditionReady)
+				Expect(ready.Status).To(Equal(gatewayv2alpha1.ConditionFalse))
+				Expect(ready.Reason).To(Equal("HostConflict"))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionVirtualServiceReady).Status).To(Equal(gatewayv2alpha1.ConditionUnknown))
+
+				vsList := istiov1alpha3.VirtualServiceList{}
+				err = ts.mgr.GetClient().List(context.Background(), &vsList)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vsList.Items).To(BeEmpty())
+			})
+
+			It("should back off the retries of a transient error", func() {
+				firstAPI := fixAPI()
+				firstAPI.ObjectMeta.Name = "first"
+				firstAPI.ObjectMeta.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+				testAPI := fixAPI()
+				testAPI.ObjectMeta.CreationTimestamp = metav1.Now()
+
+				ts = getTestSuite(firstAPI, testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				var result reconcile.Result
+				var err error
+				for i := 0; i < 3; i++ {
+					result, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+					Expect(err).ToNot(HaveOccurred())
+				}
+				Expect(result.RequeueAfter).To(Equal(20 * time.Second))
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.RetryCount).To(Equal(int32(3)))
+			})
+
+			It("should not retry an invalid Gate", func() {
+				testAPI := fixOauthAPI()
+				testAPI.Spec.Auth.Config = &runtime.RawExtension{Raw: []byte(`{"paths":[]}`)}
+
+				ts = getTestSuite(testAPI)
+				recorder := record.NewFakeRecorder(10)
+				reconciler := &controllers.ApiReconciler{Client: ts.mgr.GetClient(), Log: ctrl.Log.WithName("controllers").WithName("Api"), Recorder: recorder}
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+				Expect(result.RequeueAfter).To(BeZero())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.GateStatus.Code).To(Equal(gatewayv2alpha1.STATUS_ERROR))
+				Expect(res.Status.RetryCount).To(BeZero())
+				Expect(drainEvents(recorder)).To(ConsistOf("Warning ValidationFailed supplied config does not match internal template"))
+
+				validated := findCondition(res, gatewayv2alpha1.ConditionValidated)
+				Expect(validated.Status).To(Equal(gatewayv2alpha1.ConditionFalse))
+				Expect(validated.Reason).To(Equal("ValidationFailed"))
+				Expect(findCondition(res, gatewayv2alpha1.ConditionReady).Reason).To(Equal("ValidationFailed"))
+			})
+
+			It("should record the lifecycle of generated resources", func() {
+				testAPI := fixOauthAPI()
+
+				ts = getTestSuite(testAPI)
+				recorder := record.NewFakeRecorder(10)
+				reconciler := &controllers.ApiReconciler{Client: ts.mgr.GetClient(), Log: ctrl.Log.WithName("controllers").WithName("Api"), Recorder: recorder}
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(drainEvents(recorder)).To(ConsistOf(
+					"Normal Created Rule /test-test-0 created",
+					"Normal Created Rule /test-test-1 created",
+					"Normal Created VirtualService /test-test created",
+				))
+
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, testAPI)
+				Expect(err).ToNot(HaveOccurred())
+				authStrategy = gatewayv2alpha1.PASSTHROUGH
+				testAPI.Spec.Auth.Config = nil
+				testAPI.Generation = 2
+				err = ts.mgr.GetClient().Update(context.Background(), testAPI)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(drainEvents(recorder)).To(ConsistOf(
+					"Normal Updated VirtualService /test-test updated",
+					"Normal Deleted Rule /test-test-0 deleted",
+					"Normal Deleted Rule /test-test-1 deleted",
+				))
+			})
+
+			It("should restore a modified VirtualService", func() {
+				testAPI := fixAPI()
+
+				ts = getTestSuite(testAPI)
+				recorder := record.NewFakeRecorder(10)
+				reconciler := &controllers.ApiReconciler{Client: ts.mgr.GetClient(), Log: ctrl.Log.WithName("controllers").WithName("Api"), Recorder: recorder}
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				vsName := types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + serviceName}
+				vs := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), vsName, &vs)
+				Expect(err).ToNot(HaveOccurred())
+				vs.Spec.Hosts = []string{"modified.bar"}
+				err = ts.mgr.GetClient().Update(context.Background(), &vs)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				restored := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), vsName, &restored)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(restored.Spec.Hosts).To(ConsistOf(host))
+				Expect(drainEvents(recorder)).To(ContainElement("Warning Drifted VirtualService /test-test was modified outside of the Gate, restoring it"))
+			})
+
+			It("should restore a deleted VirtualService", func() {
+				testAPI := fixAPI()
+
+				ts = getTestSuite(testAPI)
+				recorder := record.NewFakeRecorder(10)
+				reconciler := &controllers.ApiReconciler{Client: ts.mgr.GetClient(), Log: ctrl.Log.WithName("controllers").WithName("Api"), Recorder: recorder}
+
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				vsName := types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + serviceName}
+				vs := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), vsName, &vs)
+				Expect(err).ToNot(HaveOccurred())
+				err = ts.mgr.GetClient().Delete(context.Background(), &vs)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+
+				restored := istiov1alpha3.VirtualService{}
+				err = ts.mgr.GetClient().Get(context.Background(), vsName, &restored)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(drainEvents(recorder)).To(ContainElement("Warning Drifted VirtualService /test-test was deleted outside of the Gate, restoring it"))
+			})
+
+			It("should create policy in JWT mode", func() {
+				testAPI := fixJWTAPI()
+
+				ts = getTestSuite(testAPI, fixService())
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.AccessRuleStatus.Code).To(Equal(gatewayv2alpha1.STATUS_SKIPPED))
+				Expect(res.Status.PolicyServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(res.Status.VirtualServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+				Expect(res.Status.GateStatus.Code).To(Equal(gatewayv2alpha1.STATUS_OK))
+
+				requestAuthentication := securityv1beta1.RequestAuthentication{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + serviceName}, &requestAuthentication)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requestAuthentication.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": serviceName}))
+				Expect(requestAuthentication.Spec.JWTRules[0].Issuer).To(Equal("https://dex.kyma.local"))
+
+				authorizationPolicy := securityv1beta1.AuthorizationPolicy{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name + "-" + serviceName}, &authorizationPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(authorizationPolicy.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": serviceName}))
+				Expect(authorizationPolicy.Spec.Rules[0].From[0].Source.RequestPrincipals).To(Equal([]string{"*"}))
+			})
+
+			It("should retry in JWT mode until the service exists", func() {
+				testAPI := fixJWTAPI()
+
+				ts = getTestSuite(testAPI)
+				reconciler := getAPIReconciler(ts.mgr)
+
+				result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testAPI.Name}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+				res := gatewayv2alpha1.Gate{}
+				err = ts.mgr.GetClient().Get(context.Background(), types.NamespacedName{Namespace: testAPI.Namespace, Name: testAPI.Name}, &res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Status.PolicyServiceStatus.Code).To(Equal(gatewayv2alpha1.STATUS_ERROR))
 			})
 		})
 	})
@@ -85,10 +811,64 @@ func fixAPI() *gatewayv2alpha1.Gate {
 	}
 }
 
+func fixOauthAPI() *gatewayv2alpha1.Gate {
+	api := fixAPI()
+	authStrategy = gatewayv2alpha1.OAUTH
+	api.Spec.Auth.Config = &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/foo","scopes":["read"],"methods":["GET"]},{"path":"/bar","methods":["POST"]}]}`)}
+
+	return api
+}
+
+func fixJWTAPI() *gatewayv2alpha1.Gate {
+	api := fixAPI()
+	authStrategy = gatewayv2alpha1.JWT
+	api.Spec.Auth.Config = &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys"}`)}
+
+	return api
+}
+
+// fixService returns the service of the Gates, selecting its pods by their app label
+func fixService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceName},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": serviceName}},
+	}
+}
+
+// keyDigest returns the hex SHA-256 digest of the API key, as held by the Lua script of the APIKEY strategy
+func keyDigest(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func findCondition(api gatewayv2alpha1.Gate, conditionType gatewayv2alpha1.ConditionType) gatewayv2alpha1.Condition {
+	for _, condition := range api.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition
+		}
+	}
+	Fail("condition " + string(conditionType) + " not found")
+	return gatewayv2alpha1.Condition{}
+}
+
 func getAPIReconciler(mgr manager.Manager) reconcile.Reconciler {
 	return &controllers.ApiReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Api"),
+		Client:               mgr.GetClient(),
+		Log:                  ctrl.Log.WithName("controllers").WithName("Api"),
+		Recorder:             record.NewFakeRecorder(100),
+		CertificateNamespace: "istio-system",
 	}
 }
 
@@ -99,7 +879,13 @@ type testSuite struct {
 func getTestSuite(objects ...runtime.Object) *testSuite {
 	err := gatewayv2alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
-	err = networkingv1alpha3.AddToScheme(scheme.Scheme)
+	err = istiov1alpha3.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = rulev1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = securityv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = certmanagerv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
 	return &testSuite{
@@ -107,6 +893,19 @@ func getTestSuite(objects ...runtime.Object) *testSuite {
 	}
 }
 
+// missingKindClient fails to list the kind as if its CRD was not installed
+type missingKindClient struct {
+	client.Client
+	missing runtime.Object
+}
+
+func (c *missingKindClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	if reflect.TypeOf(list) == reflect.TypeOf(c.missing) {
+		return &meta.NoKindMatchError{GroupKind: list.GetObjectKind().GroupVersionKind().GroupKind()}
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
 type fakeManager struct {
 	client client.Client
 	sch    *runtime.Scheme
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewClient creates the client of the manager, reading the Secrets from the API server instead of the cache, so that
+// the controller does not cache all the Secrets of the cluster
+func NewClient(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.DelegatingClient{
+		Reader: &uncachedSecretsReader{
+			cacheReader: &client.DelegatingReader{
+				CacheReader:  cache,
+				ClientReader: c,
+			},
+			clientReader: c,
+		},
+		Writer:       c,
+		StatusClient: c,
+	}, nil
+}
+
+// uncachedSecretsReader reads the Secrets with the client reader and all the other objects with the cache reader
+type uncachedSecretsReader struct {
+	cacheReader  client.Reader
+	clientReader client.Reader
+}
+
+func (r *uncachedSecretsReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		return r.clientReader.Get(ctx, key, obj)
+	}
+	return r.cacheReader.Get(ctx, key, obj)
+}
+
+func (r *uncachedSecretsReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	if _, ok := list.(*corev1.SecretList); ok {
+		return r.clientReader.List(ctx, list, opts...)
+	}
+	return r.cacheReader.List(ctx, list, opts...)
+}
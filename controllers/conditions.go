@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"time"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	reasonReady            = "Ready"
+	reasonValid            = "Valid"
+	reasonValidationFailed = "ValidationFailed"
+	reasonHostConflict     = "HostConflict"
+	reasonProcessed        = "Processed"
+	reasonProcessingFailed = "ProcessingFailed"
+	reasonNotRequired      = "NotRequired"
+	reasonNotProcessed     = "NotProcessed"
+)
+
+// updateConditions derives the conditions of the Gate from the reason its reconcile ended with and the statuses of
+// its generated resources. Resources are not processed when the Gate is invalid or its host is claimed by another
+// Gate, they are skipped when its auth strategy does not require them.
+func updateConditions(api *gatewayv2alpha1.Gate, reason string, APIStatus, virtualServiceStatus, policyStatus, accessRuleStatus, rateLimitStatus, tlsStatus *gatewayv2alpha1.GatewayResourceStatus, now time.Time) {
+	switch reason {
+	case reasonValidationFailed:
+		setCondition(api, gatewayv2alpha1.ConditionValidated, gatewayv2alpha1.ConditionFalse, reasonValidationFailed, APIStatus.Description, now)
+		setNotProcessedConditions(api, now)
+	case reasonHostConflict:
+		setCondition(api, gatewayv2alpha1.ConditionValidated, gatewayv2alpha1.ConditionTrue, reasonValid, "", now)
+		setNotProcessedConditions(api, now)
+	default:
+		setCondition(api, gatewayv2alpha1.ConditionValidated, gatewayv2alpha1.ConditionTrue, reasonValid, "", now)
+		setResourceCondition(api, gatewayv2alpha1.ConditionVirtualServiceReady, virtualServiceStatus, now)
+		setResourceCondition(api, gatewayv2alpha1.ConditionAccessRulesReady, accessRuleStatus, now)
+		setResourceCondition(api, gatewayv2alpha1.ConditionPolicyReady, policyStatus, now)
+		setResourceCondition(api, gatewayv2alpha1.ConditionRateLimitReady, rateLimitStatus, now)
+		setResourceCondition(api, gatewayv2alpha1.ConditionTLSReady, tlsStatus, now)
+	}
+
+	if reason == reasonReady {
+		setCondition(api, gatewayv2alpha1.ConditionReady, gatewayv2alpha1.ConditionTrue, reasonReady, "", now)
+	} else {
+		setCondition(api, gatewayv2alpha1.ConditionReady, gatewayv2alpha1.ConditionFalse, reason, APIStatus.Description, now)
+	}
+}
+
+func setNotProcessedConditions(api *gatewayv2alpha1.Gate, now time.Time) {
+	for _, conditionType := range []gatewayv2alpha1.ConditionType{
+		gatewayv2alpha1.ConditionVirtualServiceReady,
+		gatewayv2alpha1.ConditionAccessRulesReady,
+		gatewayv2alpha1.ConditionPolicyReady,
+		gatewayv2alpha1.ConditionRateLimitReady,
+		gatewayv2alpha1.ConditionTLSReady,
+	} {
+		setCondition(api, conditionType, gatewayv2alpha1.ConditionUnknown, reasonNotProcessed, "", now)
+	}
+}
+
+func setResourceCondition(api *gatewayv2alpha1.Gate, conditionType gatewayv2alpha1.ConditionType, status *gatewayv2alpha1.GatewayResourceStatus, now time.Time) {
+	switch status.Code {
+	case gatewayv2alpha1.STATUS_OK:
+		setCondition(api, conditionType, gatewayv2alpha1.ConditionTrue, reasonProcessed, status.Description, now)
+	case gatewayv2alpha1.STATUS_SKIPPED:
+		setCondition(api, conditionType, gatewayv2alpha1.ConditionTrue, reasonNotRequired, status.Description, now)
+	default:
+		setCondition(api, conditionType, gatewayv2alpha1.ConditionFalse, reasonProcessingFailed, status.Description, now)
+	}
+}
+
+// setCondition adds or updates the condition of the given type, keeping its transition time if the status did not change
+func setCondition(api *gatewayv2alpha1.Gate, conditionType gatewayv2alpha1.ConditionType, status gatewayv2alpha1.ConditionStatus, reason, message string, now time.Time) {
+	condition := gatewayv2alpha1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: api.Generation,
+		LastTransitionTime: v1.Time{Time: now},
+		Reason:             reason,
+		Message:            message,
+	}
+
+	for i := range api.Status.Conditions {
+		existing := &api.Status.Conditions[i]
+		if existing.Type != conditionType {
+			continue
+		}
+		if existing.Status == status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = condition
+		return
+	}
+	api.Status.Conditions = append(api.Status.Conditions, condition)
+}
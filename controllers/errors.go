@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/kyma-incubator/api-gateway/internal/processing"
+	"github.com/pkg/errors"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = 5 * time.Minute
+)
+
+// permanentError marks an error which cannot be fixed by retrying, only by changing the Gate
+type permanentError struct {
+	error
+}
+
+func permanent(err error) error {
+	return &permanentError{err}
+}
+
+// isPermanent tells if the error is permanent. Errors are considered transient unless marked as permanent, reporting
+// a resource in the way of the generated ones, or rejected by the API server as invalid, so that conflicts, timeouts and CRDs which are not installed yet are retried.
+func isPermanent(err error) bool {
+	if _, ok := err.(*permanentError); ok {
+		return true
+	}
+
+	cause := errors.Cause(err)
+	if _, ok := cause.(*processing.NotGeneratedError); ok {
+		return true
+	}
+	return apierrs.IsInvalid(cause) || apierrs.IsBadRequest(cause)
+}
+
+// retryDelay doubles the delay with every retry, up to retryMaxDelay
+func retryDelay(retries int32) time.Duration {
+	delay := retryBaseDelay
+	for i := int32(1); i < retries; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ignoreStatusUpdates filters out the updates of Gates which only change their status, so that the controller does
+// not reconcile its own status updates and the retries of failed Gates keep their backoff. The periodic resyncs,
+// which do not change the Gate, are kept to correct the drift of the resources no event is received for.
+func ignoreStatusUpdates() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldAPI, ok := e.ObjectOld.(*gatewayv2alpha1.Gate)
+			if !ok {
+				return true
+			}
+			newAPI, ok := e.ObjectNew.(*gatewayv2alpha1.Gate)
+			if !ok {
+				return true
+			}
+			if oldAPI.ResourceVersion == newAPI.ResourceVersion {
+				return true
+			}
+			return !onlyStatusChanged(oldAPI, newAPI)
+		},
+	}
+}
+
+func onlyStatusChanged(oldAPI, newAPI *gatewayv2alpha1.Gate) bool {
+	oldCopy := oldAPI.DeepCopy()
+	newCopy := newAPI.DeepCopy()
+
+	oldCopy.Status = gatewayv2alpha1.GateStatus{}
+	newCopy.Status = gatewayv2alpha1.GateStatus{}
+	oldCopy.ResourceVersion = ""
+	newCopy.ResourceVersion = ""
+
+	return equality.Semantic.DeepEqual(oldCopy, newCopy)
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// apiKeySecretsInformer watches the Secrets labeled as holding API keys only, so that the controller does not cache
+// all the Secrets of the cluster. The informer is started along with the manager.
+func apiKeySecretsInformer(mgr ctrl.Manager) (cache.Informer, error) {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = gatewayv2alpha1.APIKeySecretLabel
+	}))
+	informer := factory.Core().V1().Secrets().Informer()
+
+	err = mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		factory.Start(stop)
+		<-stop
+		return nil
+	}))
+	return informer, err
+}
+
+// gatesForSecret maps a Secret to the Gates of its namespace using the APIKEY strategy whose selector matches the
+// Secret, so that their keys are reloaded when the Secret changes
+func (r *ApiReconciler) gatesForSecret() handler.ToRequestsFunc {
+	return func(secret handler.MapObject) []reconcile.Request {
+		var apis gatewayv2alpha1.GateList
+		err := r.Client.List(context.Background(), &apis, client.InNamespace(secret.Meta.GetNamespace()))
+		if err != nil {
+			r.Log.Error(err, "Listing the Gates selecting a Secret failed", "secret", secret.Meta.GetName())
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, api := range apis.Items {
+			if selectsSecret(&api, secret.Meta.GetLabels()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: api.Namespace, Name: api.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+func selectsSecret(api *gatewayv2alpha1.Gate, secretLabels map[string]string) bool {
+	if api.Spec.Auth == nil || api.Spec.Auth.Name == nil || *api.Spec.Auth.Name != gatewayv2alpha1.APIKEY || api.Spec.Auth.Config == nil {
+		return false
+	}
+
+	var config gatewayv2alpha1.APIKeyModeConfig
+	if json.Unmarshal(api.Spec.Auth.Config.Raw, &config) != nil || config.SecretSelector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(config.SecretSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(secretLabels))
+}
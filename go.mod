@@ -8,8 +8,11 @@ require (
 	github.com/onsi/ginkgo v1.6.0
 	github.com/onsi/gomega v1.4.2
 	github.com/pkg/errors v0.8.1
+	github.com/prometheus/client_golang v0.9.0
 	github.com/stretchr/testify v1.3.0
+	github.com/yuin/gopher-lua v0.0.0-20180827083657-b942cacc89fe
 	gotest.tools v2.2.0+incompatible
+	k8s.io/api v0.0.0-20190409021203-6e4e0e4f393b
 	k8s.io/apimachinery v0.0.0-20190404173353-6a84e37a896d
 	k8s.io/client-go v11.0.1-0.20190409021438-1a26190bd76a+incompatible
 	knative.dev/pkg v0.0.0-20190807140856-4707aad818fe
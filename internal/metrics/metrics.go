@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "api_gateway"
+
+var (
+	// ReconcileTotal counts the reconciles of Gates per auth strategy and result
+	ReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_total",
+		Help:      "Total number of Gate reconciles per auth strategy and result",
+	}, []string{"strategy", "result"})
+
+	// ReconcileDuration observes the duration of the reconciles of Gates per auth strategy
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of Gate reconciles per auth strategy",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"strategy"})
+
+	// ValidationFailuresTotal counts the Gates rejected by the controller, as invalid or conflicting with another Gate.
+	// The validation errors are described by the events and the status of the Gates.
+	ValidationFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "validation_failures_total",
+		Help:      "Total number of Gate validation failures",
+	})
+
+	// GeneratedResourcesTotal counts the changes of the generated resources per kind and operation
+	GeneratedResourcesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "generated_resources_total",
+		Help:      "Total number of generated resources created, updated or deleted per kind",
+	}, []string{"kind", "operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(ReconcileTotal, ReconcileDuration, ValidationFailuresTotal, GeneratedResourcesTotal)
+}
+
+var gatesDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "gates"),
+	"Number of Gates per status code",
+	[]string{"code"}, nil,
+)
+
+// gateStatusCollector counts the Gates per status code whenever the metrics are scraped
+type gateStatusCollector struct {
+	reader client.Reader
+}
+
+// NewGateStatusCollector returns a collector of the number of Gates per status code. Gates not processed yet are
+// counted with an empty code.
+func NewGateStatusCollector(reader client.Reader) prometheus.Collector {
+	return &gateStatusCollector{reader: reader}
+}
+
+func (c *gateStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- gatesDesc
+}
+
+func (c *gateStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	gates := &gatewayv2alpha1.GateList{}
+	err := c.reader.List(context.Background(), gates)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(gatesDesc, err)
+		return
+	}
+
+	counts := map[gatewayv2alpha1.StatusCode]int{
+		gatewayv2alpha1.STATUS_OK:    0,
+		gatewayv2alpha1.STATUS_ERROR: 0,
+	}
+	for _, gate := range gates.Items {
+		var code gatewayv2alpha1.StatusCode
+		if gate.Status.GateStatus != nil {
+			code = gate.Status.GateStatus.Code
+		}
+		counts[code]++
+	}
+
+	for code, count := range counts {
+		ch <- prometheus.MustNewConstMetric(gatesDesc, prometheus.GaugeValue, float64(count), string(code))
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGateStatusCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, gatewayv2alpha1.AddToScheme(scheme))
+
+	reader := fake.NewFakeClientWithScheme(scheme,
+		fixGate("ok-1", gatewayv2alpha1.STATUS_OK),
+		fixGate("ok-2", gatewayv2alpha1.STATUS_OK),
+		fixGate("error", gatewayv2alpha1.STATUS_ERROR),
+		fixGate("new", ""),
+	)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewGateStatusCollector(reader))
+
+	families, err := registry.Gather()
+	assert.NilError(t, err)
+	assert.Equal(t, len(families), 1)
+	assert.Equal(t, families[0].GetName(), "api_gateway_gates")
+
+	counts := map[string]float64{}
+	for _, metric := range families[0].GetMetric() {
+		counts[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+	}
+	assert.DeepEqual(t, counts, map[string]float64{"OK": 2, "ERROR": 1, "": 1})
+}
+
+func fixGate(name string, code gatewayv2alpha1.StatusCode) *gatewayv2alpha1.Gate {
+	gate := &gatewayv2alpha1.Gate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	if code != "" {
+		gate.Status.GateStatus = &gatewayv2alpha1.GatewayResourceStatus{Code: code}
+	}
+	return gate
+}
@@ -0,0 +1,288 @@
+package processing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis/istio/common/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	luaFilter        = "envoy.filters.http.lua"
+	luaPerRouteType  = "type.googleapis.com/envoy.extensions.filters.http.lua.v3.LuaPerRoute"
+	defaultKeyHeader = "x-api-key"
+	// apiKeySecretKey is the entry of the selected Secrets holding the accepted key
+	apiKeySecretKey = "api-key"
+	// keyVerifiedHeader carries the token of the Gate on the requests whose key was verified by the script
+	keyVerifiedHeader = "x-api-gateway-key-verified"
+	// keyUnverifiedStatus is returned by the gateway for the requests the script did not run on
+	keyUnverifiedStatus = 403
+)
+
+type apiKey struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// Process checks the keys of the requests at the gateway with a Lua script, configured on the virtual hosts of the
+// Gate by an EnvoyFilter in the namespace of its gateway. The script only holds the SHA-256 digests of the keys, so
+// that the readers of the EnvoyFilter and of the configuration of the gateway cannot recover them. The Lua filter
+// itself is installed on the gateway once, see config/istio. The script is regenerated whenever the selected Secrets
+// change. The routes of the Gate only match the requests the script marked as verified, the other ones are denied by
+// the gateway, so that no request reaches the services when the script does not run, e.g. when the Lua filter is not
+// installed on the gateway.
+func (a *apiKey) Process(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	var apiKeyConfig gatewayv2alpha1.APIKeyModeConfig
+
+	err := json.Unmarshal(api.Spec.Auth.Config.Raw, &apiKeyConfig)
+	if err != nil {
+		return err
+	}
+
+	digests, err := a.readKeyDigests(ctx, api, &apiKeyConfig)
+	if err != nil {
+		return err
+	}
+
+	token := keyVerifiedToken(api)
+	spec, err := generateAPIKeyFilterSpec(api, &apiKeyConfig, digests, token)
+	if err != nil {
+		return err
+	}
+
+	err = processGatewayEnvoyFilter(ctx, a.Client, a.Recorder, api, apiKeyFilterName(api), spec)
+	if err != nil {
+		return err
+	}
+
+	return (&passthrough{Client: a.Client, Recorder: a.Recorder, keyVerifiedToken: token}).Process(ctx, api)
+}
+
+// keyVerifiedToken returns the value of the header marking the verified requests of the Gate. It is derived from
+// the UID of the Gate, which the clients outside of the cluster do not know, so they cannot forge the header.
+func keyVerifiedToken(api *gatewayv2alpha1.Gate) string {
+	return keyDigest([]byte("key-verified/" + string(api.ObjectMeta.UID)))
+}
+
+// applyKeyVerificationPolicy restricts the HTTP routes to the requests carrying the token set by the script, and
+// removes the token from the requests forwarded to the services. The remaining requests match a last route aborting
+// them. The script runs on that route as well, the route of the request is then selected again once it is marked.
+func applyKeyVerificationPolicy(spec *istiov1alpha3.VirtualServiceSpec, token string) {
+	if len(spec.HTTP) == 0 {
+		return
+	}
+	denyRoute := istiov1alpha3.HTTPRoute{
+		Route: append([]istiov1alpha3.HTTPRouteDestination(nil), spec.HTTP[len(spec.HTTP)-1].Route...),
+		Fault: &istiov1alpha3.HTTPFaultInjection{
+			Abort: &istiov1alpha3.InjectAbort{HTTPStatus: keyUnverifiedStatus},
+		},
+	}
+	for i := range spec.HTTP {
+		httpRoute := &spec.HTTP[i]
+		if len(httpRoute.Match) == 0 {
+			httpRoute.Match = []istiov1alpha3.HTTPMatchRequest{{}}
+		}
+		for j := range httpRoute.Match {
+			if httpRoute.Match[j].Headers == nil {
+				httpRoute.Match[j].Headers = map[string]v1alpha1.StringMatch{}
+			}
+			httpRoute.Match[j].Headers[keyVerifiedHeader] = v1alpha1.StringMatch{Exact: token}
+		}
+		removeRequestHeader(httpRoute, keyVerifiedHeader)
+	}
+	spec.HTTP = append(spec.HTTP, denyRoute)
+}
+
+// readKeyDigests returns the sorted hex SHA-256 digests of the keys of the selected Secrets in the namespace of the
+// Gate
+func (a *apiKey) readKeyDigests(ctx context.Context, api *gatewayv2alpha1.Gate, config *gatewayv2alpha1.APIKeyModeConfig) ([]string, error) {
+	selector, err := k8sMeta.LabelSelectorAsSelector(config.SecretSelector)
+	if err != nil {
+		return nil, err
+	}
+	labeled, err := labels.NewRequirement(gatewayv2alpha1.APIKeySecretLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector = selector.Add(*labeled)
+
+	var secrets corev1.SecretList
+	err = a.Client.List(ctx, &secrets, client.UseListOptions(&client.ListOptions{Namespace: api.GetNamespace(), LabelSelector: selector}))
+	if err != nil {
+		return nil, err
+	}
+
+	unique := map[string]bool{}
+	for _, secret := range secrets.Items {
+		if key, ok := secret.Data[apiKeySecretKey]; ok && len(key) > 0 {
+			unique[keyDigest(key)] = true
+		}
+	}
+
+	digests := make([]string, 0, len(unique))
+	for digest := range unique {
+		digests = append(digests, digest)
+	}
+	sort.Strings(digests)
+	return digests, nil
+}
+
+func keyDigest(key []byte) string {
+	digest := sha256.Sum256(key)
+	return hex.EncodeToString(digest[:])
+}
+
+func generateAPIKeyFilterSpec(api *gatewayv2alpha1.Gate, config *gatewayv2alpha1.APIKeyModeConfig, digests []string, token string) (*istiov1alpha3.EnvoyFilterSpec, error) {
+	value, err := json.Marshal(map[string]interface{}{
+		"typed_per_filter_config": map[string]interface{}{
+			luaFilter: map[string]interface{}{
+				"@type":    typedStructType,
+				"type_url": luaPerRouteType,
+				"value": map[string]interface{}{
+					"source_code": map[string]interface{}{"inline_string": generateAPIKeyScript(config, digests, token)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return generateVirtualHostPatches(api, value), nil
+}
+
+// generateAPIKeyScript generates the Lua script rejecting the requests without one of the keys, compared by their
+// digests, and marking the other ones with the token. The key is removed from the request, whether sent in a header
+// or in a query parameter, so that it does not reach the services and their access logs.
+func generateAPIKeyScript(config *gatewayv2alpha1.APIKeyModeConfig, digests []string, token string) string {
+	var script strings.Builder
+
+	script.WriteString(luaSHA256)
+	script.WriteString("local digests = {\n")
+	for _, digest := range digests {
+		fmt.Fprintf(&script, "  [%s] = true,\n", luaQuote(digest))
+	}
+	script.WriteString("}\n")
+	script.WriteString("function envoy_on_request(request_handle)\n")
+	if config.QueryParam != "" {
+		param := luaPatternQuote(config.QueryParam)
+		script.WriteString("  local path = request_handle:headers():get(\":path\") or \"\"\n")
+		fmt.Fprintf(&script, "  local key = string.match(path, \"[?&]%s=([^&#]*)\")\n", param)
+		fmt.Fprintf(&script, "  local stripped = string.gsub(path, \"([?&])%s=[^&#]*\", \"%%1\")\n", param)
+		script.WriteString("  stripped = string.gsub(stripped, \"([?&])&+\", \"%1\")\n")
+		script.WriteString("  stripped = string.gsub(stripped, \"[?&]$\", \"\")\n")
+		script.WriteString("  request_handle:headers():replace(\":path\", stripped)\n")
+	} else {
+		header := config.Header
+		if header == "" {
+			header = defaultKeyHeader
+		}
+		fmt.Fprintf(&script, "  local key = request_handle:headers():get(%s)\n", luaQuote(header))
+		fmt.Fprintf(&script, "  request_handle:headers():remove(%s)\n", luaQuote(header))
+	}
+	script.WriteString("  if key == nil or not digests[sha256(key)] then\n")
+	script.WriteString("    request_handle:respond({[\":status\"] = \"401\"}, \"Unauthorized\")\n")
+	script.WriteString("    return\n")
+	script.WriteString("  end\n")
+	fmt.Fprintf(&script, "  request_handle:headers():replace(%s, %s)\n", luaQuote(keyVerifiedHeader), luaQuote(token))
+	script.WriteString("end\n")
+
+	return script.String()
+}
+
+// luaQuote returns a Lua string literal of the value, escaping all but the characters safe in any key
+func luaQuote(value string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if isAlphanumeric(c) || strings.IndexByte("-_.~+/=:", c) >= 0 {
+			quoted.WriteByte(c)
+		} else {
+			fmt.Fprintf(&quoted, "\\%03d", c)
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
+
+// luaPatternQuote escapes the magic characters of Lua patterns in a validated query parameter name
+func luaPatternQuote(value string) string {
+	var quoted strings.Builder
+	for i := 0; i < len(value); i++ {
+		if !isAlphanumeric(value[i]) {
+			quoted.WriteByte('%')
+		}
+		quoted.WriteByte(value[i])
+	}
+	return quoted.String()
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func apiKeyFilterName(api *gatewayv2alpha1.Gate) string {
+	return fmt.Sprintf("%s-%s-apikey", api.ObjectMeta.Namespace, api.ObjectMeta.Name)
+}
+
+// luaSHA256 defines the sha256 function of the Lua scripts, returning the hex digest of a string. The LuaJIT of
+// Envoy has no hash function of its own.
+const luaSHA256 = `local bit = require("bit")
+local band, bor, bxor, bnot = bit.band, bit.bor, bit.bxor, bit.bnot
+local lshift, rshift, ror, tobit, tohex = bit.lshift, bit.rshift, bit.ror, bit.tobit, bit.tohex
+local k = {
+  0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+  0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+  0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+  0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+  0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+  0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+  0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+  0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+local function sha256(message)
+  local bits = #message * 8
+  message = message .. "\128" .. string.rep("\0", (119 - #message % 64) % 64) .. "\0\0\0\0" ..
+    string.char(band(rshift(bits, 24), 255), band(rshift(bits, 16), 255), band(rshift(bits, 8), 255), band(bits, 255))
+  local h = {0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19}
+  local w = {}
+  for chunk = 1, #message, 64 do
+    for i = 0, 15 do
+      local b1, b2, b3, b4 = string.byte(message, chunk + i * 4, chunk + i * 4 + 3)
+      w[i] = bor(lshift(b1, 24), lshift(b2, 16), lshift(b3, 8), b4)
+    end
+    for i = 16, 63 do
+      local s0 = bxor(ror(w[i - 15], 7), ror(w[i - 15], 18), rshift(w[i - 15], 3))
+      local s1 = bxor(ror(w[i - 2], 17), ror(w[i - 2], 19), rshift(w[i - 2], 10))
+      w[i] = tobit(w[i - 16] + s0 + w[i - 7] + s1)
+    end
+    local a, b, c, d, e, f, g, hh = h[1], h[2], h[3], h[4], h[5], h[6], h[7], h[8]
+    for i = 0, 63 do
+      local t1 = tobit(hh + bxor(ror(e, 6), ror(e, 11), ror(e, 25)) + bxor(band(e, f), band(bnot(e), g)) + k[i + 1] + w[i])
+      local t2 = tobit(bxor(ror(a, 2), ror(a, 13), ror(a, 22)) + bxor(band(a, b), band(a, c), band(b, c)))
+      hh, g, f, e = g, f, e, tobit(d + t1)
+      d, c, b, a = c, b, a, tobit(t1 + t2)
+    end
+    h[1], h[2], h[3], h[4] = tobit(h[1] + a), tobit(h[2] + b), tobit(h[3] + c), tobit(h[4] + d)
+    h[5], h[6], h[7], h[8] = tobit(h[5] + e), tobit(h[6] + f), tobit(h[7] + g), tobit(h[8] + hh)
+  end
+  local digest = ""
+  for i = 1, 8 do
+    digest = digest .. tohex(h[i])
+  end
+  return digest
+end
+`
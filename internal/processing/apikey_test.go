@@ -0,0 +1,250 @@
+package processing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"strings"
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestGenerateAPIKeyFilterSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	config := &gatewayv2alpha1.APIKeyModeConfig{Header: "x-tooling-key"}
+
+	spec, err := generateAPIKeyFilterSpec(exampleAPI, config, []string{keyDigest([]byte("s3cr3t"))}, "token")
+	assert.Nil(err)
+
+	assert.Len(spec.ConfigPatches, 2)
+	assert.Equal(spec.ConfigPatches[0].ApplyTo, istiov1alpha3.ApplyToVirtualHost)
+	assert.Equal(spec.ConfigPatches[0].Match.RouteConfiguration.Vhost.Name, serviceHost+":80")
+	assert.Equal(spec.ConfigPatches[1].Match.RouteConfiguration.Vhost.Name, serviceHost+":443")
+
+	var value struct {
+		TypedPerFilterConfig map[string]struct {
+			TypeURL string `json:"type_url"`
+			Value   struct {
+				SourceCode struct {
+					InlineString string `json:"inline_string"`
+				} `json:"source_code"`
+			} `json:"value"`
+		} `json:"typed_per_filter_config"`
+	}
+	err = json.Unmarshal(spec.ConfigPatches[0].Patch.Value.Raw, &value)
+	assert.Nil(err)
+
+	lua := value.TypedPerFilterConfig["envoy.filters.http.lua"]
+	assert.Equal(lua.TypeURL, "type.googleapis.com/envoy.extensions.filters.http.lua.v3.LuaPerRoute")
+	assert.Contains(lua.Value.SourceCode.InlineString, `["4e738ca5563c06cfd0018299933d58db1dd8bf97f6973dc99bf6cdc64b5550bd"] = true,`)
+	assert.NotContains(lua.Value.SourceCode.InlineString, "s3cr3t")
+	assert.Contains(lua.Value.SourceCode.InlineString, "not digests[sha256(key)]")
+	assert.Contains(lua.Value.SourceCode.InlineString, `request_handle:headers():get("x-tooling-key")`)
+	assert.Contains(lua.Value.SourceCode.InlineString, `request_handle:headers():remove("x-tooling-key")`)
+	assert.Contains(lua.Value.SourceCode.InlineString, `request_handle:headers():replace("x-api-gateway-key-verified", "token")`)
+}
+
+func TestGenerateAPIKeyScript(t *testing.T) {
+	assert := assert.New(t)
+
+	script := generateAPIKeyScript(&gatewayv2alpha1.APIKeyModeConfig{}, nil, "token")
+	assert.Contains(script, "local function sha256(message)")
+	assert.Contains(script, "local digests = {\n}")
+	assert.Contains(script, `request_handle:headers():get("x-api-key")`)
+	assert.Contains(script, `request_handle:respond({[":status"] = "401"}, "Unauthorized")`)
+
+	script = generateAPIKeyScript(&gatewayv2alpha1.APIKeyModeConfig{QueryParam: "api_key.v-2"}, []string{"key"}, "token")
+	assert.Contains(script, `string.match(path, "[?&]api%_key%.v%-2=([^&#]*)")`)
+	assert.Contains(script, `string.gsub(path, "([?&])api%_key%.v%-2=[^&#]*", "%1")`)
+	assert.Contains(script, `request_handle:headers():replace(":path", stripped)`)
+	assert.NotContains(script, "remove(")
+
+	assert.Equal(apiKeyFilterName(getOauthAPI()), apiNamespace+"-"+apiName+"-apikey")
+}
+
+func TestGenerateVirtualServiceWithKeyVerification(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: &serviceName, Port: &servicePort},
+		},
+	}
+	token := keyVerifiedToken(exampleAPI)
+	assert.Len(token, 64)
+	otherAPI := getOauthAPI()
+	otherAPI.UID = "a8d5b2f6-c417-11e9-bf11-4ac644044351"
+	assert.NotEqual(token, keyVerifiedToken(otherAPI))
+
+	strategy := &passthrough{keyVerifiedToken: token}
+	spec := strategy.generateVirtualServiceSpec(exampleAPI)
+
+	assert.Len(spec.HTTP, 3)
+	for _, httpRoute := range spec.HTTP[:2] {
+		assert.Equal(httpRoute.Match[0].Headers[keyVerifiedHeader].Exact, token)
+		assert.Equal(httpRoute.Headers.Request.Remove, []string{keyVerifiedHeader})
+		assert.Nil(httpRoute.Fault)
+	}
+	assert.Empty(spec.HTTP[2].Match)
+	assert.Equal(spec.HTTP[2].Route, spec.HTTP[1].Route)
+	assert.Equal(spec.HTTP[2].Fault.Abort.HTTPStatus, 403)
+}
+
+func TestLuaSHA256(t *testing.T) {
+	assert := assert.New(t)
+
+	L := newLuaState()
+	defer L.Close()
+	chunk, err := L.LoadString(luaSHA256 + "return sha256\n")
+	assert.Nil(err)
+	assert.Nil(L.CallByParam(lua.P{Fn: chunk, NRet: 1, Protect: true}))
+	sha256 := L.Get(-1)
+
+	digest := func(message string) string {
+		if err := L.CallByParam(lua.P{Fn: sha256, NRet: 1, Protect: true}, lua.LString(message)); err != nil {
+			t.Fatal(err)
+		}
+		defer L.Pop(1)
+		return L.Get(-1).String()
+	}
+
+	assert.Equal(digest(""), "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	assert.Equal(digest("abc"), "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")
+	assert.Equal(digest("abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq"), "248d6a61d20638b8e5c026930c3e6039a33ce45964ff2167f6ecedd419db06c1")
+	assert.Equal(digest(strings.Repeat("a", 1000)), "41edece42d63e8d9bf515a9ba6932e1c20cbc9f5a5d134645adb5db1b9737ea3")
+	for length := 0; length <= 130; length++ {
+		message := strings.Repeat("\x00\xff", length)[:length]
+		assert.Equal(digest(message), keyDigest([]byte(message)), "length %d", length)
+	}
+}
+
+func TestAPIKeyScript(t *testing.T) {
+	assert := assert.New(t)
+
+	digests := []string{keyDigest([]byte("s3cr3t")), keyDigest([]byte("other"))}
+
+	request := runAPIKeyScript(t, &gatewayv2alpha1.APIKeyModeConfig{}, digests, map[string]string{"x-api-key": "s3cr3t"})
+	assert.Empty(request.status)
+	assert.Equal(request.headers, map[string]string{keyVerifiedHeader: "token"})
+
+	request = runAPIKeyScript(t, &gatewayv2alpha1.APIKeyModeConfig{Header: "x-tooling-key"}, digests, map[string]string{"x-tooling-key": "wrong"})
+	assert.Equal(request.status, "401")
+	assert.Empty(request.headers)
+
+	request = runAPIKeyScript(t, &gatewayv2alpha1.APIKeyModeConfig{}, digests, map[string]string{})
+	assert.Equal(request.status, "401")
+	assert.Empty(request.headers)
+
+	query := &gatewayv2alpha1.APIKeyModeConfig{QueryParam: "api_key"}
+	request = runAPIKeyScript(t, query, digests, map[string]string{":path": "/orders?api_key=other&page=2"})
+	assert.Empty(request.status)
+	assert.Equal(request.headers, map[string]string{":path": "/orders?page=2", keyVerifiedHeader: "token"})
+
+	request = runAPIKeyScript(t, query, digests, map[string]string{":path": "/orders?page=2&api_key=s3cr3t"})
+	assert.Empty(request.status)
+	assert.Equal(request.headers, map[string]string{":path": "/orders?page=2", keyVerifiedHeader: "token"})
+
+	request = runAPIKeyScript(t, query, digests, map[string]string{":path": "/orders?xapi_key=s3cr3t"})
+	assert.Equal(request.status, "401")
+	assert.Equal(request.headers, map[string]string{":path": "/orders?xapi_key=s3cr3t"})
+}
+
+// luaRequest records the effects of the script on a request
+type luaRequest struct {
+	headers map[string]string
+	status  string
+}
+
+// runAPIKeyScript runs the generated script on a request with the headers, as the Lua filter of Envoy does
+func runAPIKeyScript(t *testing.T, config *gatewayv2alpha1.APIKeyModeConfig, digests []string, headers map[string]string) *luaRequest {
+	L := newLuaState()
+	defer L.Close()
+	if err := L.DoString(generateAPIKeyScript(config, digests, "token")); err != nil {
+		t.Fatal(err)
+	}
+
+	request := &luaRequest{headers: headers}
+	requestHeaders := L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"get": func(L *lua.LState) int {
+			if value, ok := request.headers[L.CheckString(2)]; ok {
+				L.Push(lua.LString(value))
+			} else {
+				L.Push(lua.LNil)
+			}
+			return 1
+		},
+		"remove": func(L *lua.LState) int {
+			delete(request.headers, L.CheckString(2))
+			return 0
+		},
+		"replace": func(L *lua.LState) int {
+			request.headers[L.CheckString(2)] = L.CheckString(3)
+			return 0
+		},
+	})
+	handle := L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"headers": func(L *lua.LState) int {
+			L.Push(requestHeaders)
+			return 1
+		},
+		"respond": func(L *lua.LState) int {
+			request.status = L.CheckTable(2).RawGetString(":status").String()
+			return 0
+		},
+	})
+
+	err := L.CallByParam(lua.P{Fn: L.GetGlobal("envoy_on_request"), Protect: true}, handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+// newLuaState returns a Lua interpreter with the bit module of LuaJIT, the interpreter of the Lua filter of Envoy.
+// The operations of the module work on 32-bit integers and return them as signed numbers.
+func newLuaState() *lua.LState {
+	L := lua.NewState()
+	L.PreloadModule("bit", func(L *lua.LState) int {
+		arg := func(n int) uint32 {
+			return uint32(int64(L.CheckNumber(n)))
+		}
+		result := func(value uint32) int {
+			L.Push(lua.LNumber(int32(value)))
+			return 1
+		}
+		fold := func(op func(x, y uint32) uint32) lua.LGFunction {
+			return func(L *lua.LState) int {
+				value := arg(1)
+				for n := 2; n <= L.GetTop(); n++ {
+					value = op(value, arg(n))
+				}
+				return result(value)
+			}
+		}
+		L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+			"tobit":  func(L *lua.LState) int { return result(arg(1)) },
+			"bnot":   func(L *lua.LState) int { return result(^arg(1)) },
+			"band":   fold(func(x, y uint32) uint32 { return x & y }),
+			"bor":    fold(func(x, y uint32) uint32 { return x | y }),
+			"bxor":   fold(func(x, y uint32) uint32 { return x ^ y }),
+			"lshift": func(L *lua.LState) int { return result(arg(1) << (arg(2) & 31)) },
+			"rshift": func(L *lua.LState) int { return result(arg(1) >> (arg(2) & 31)) },
+			"ror":    func(L *lua.LState) int { return result(bits.RotateLeft32(arg(1), -int(arg(2)&31))) },
+			"tohex": func(L *lua.LState) int {
+				digits := L.OptInt(2, 8)
+				L.Push(lua.LString(fmt.Sprintf("%08x", arg(1))[8-digits:]))
+				return 1
+			},
+		}))
+		return 1
+	})
+	return L
+}
@@ -0,0 +1,99 @@
+package processing
+
+import (
+	"encoding/json"
+	"fmt"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const jwtAuthenticator = "jwt"
+
+// jwtAuthenticatorConfig returns the configuration of the jwt authenticator. It is a map, marshalled with sorted
+// keys like the API server returns them, so that the stored access rules match the generated ones.
+func jwtAuthenticatorConfig(config *gatewayv2alpha1.JWTModeConfig, path string) map[string]interface{} {
+	authenticatorConfig := map[string]interface{}{
+		"jwks_urls":       []string{config.JWKSURI},
+		"trusted_issuers": []string{config.Issuer},
+	}
+	if len(config.Audiences) > 0 {
+		authenticatorConfig["target_audience"] = config.Audiences
+	}
+	if scopes := jwtScopes(config, path); len(scopes) > 0 {
+		authenticatorConfig["required_scope"] = scopes
+	}
+	return authenticatorConfig
+}
+
+// authenticatorChain returns the auth strategy of the Gate followed by its authenticators, in the order they are tried
+func authenticatorChain(api *gatewayv2alpha1.Gate) []gatewayv2alpha1.Authenticator {
+	strategy := gatewayv2alpha1.Authenticator{Name: api.Spec.Auth.Name, Config: api.Spec.Auth.Config}
+	return append([]gatewayv2alpha1.Authenticator{strategy}, api.Spec.Auth.Authenticators...)
+}
+
+// accessRuleConfig returns the config of the OAUTH authenticator of the chain, whose paths define the access rules
+func accessRuleConfig(api *gatewayv2alpha1.Gate) (*gatewayv2alpha1.OauthModeConfig, error) {
+	for _, authenticator := range authenticatorChain(api) {
+		if *authenticator.Name != gatewayv2alpha1.OAUTH {
+			continue
+		}
+
+		var oauthConfig gatewayv2alpha1.OauthModeConfig
+		err := json.Unmarshal(authenticator.Config.Raw, &oauthConfig)
+		if err != nil {
+			return nil, err
+		}
+		return &oauthConfig, nil
+	}
+	return nil, fmt.Errorf("no OAUTH authenticator defines the paths of the access rules")
+}
+
+// generateAuthenticators returns the authenticators of the access rule of the path, tried by the auth proxy in the
+// order of the chain. Each authenticator requires the scopes its config lists for the path.
+func generateAuthenticators(api *gatewayv2alpha1.Gate, option gatewayv2alpha1.Option) ([]*rulev1alpha1.Handler, error) {
+	var handlers []*rulev1alpha1.Handler
+
+	for _, authenticator := range authenticatorChain(api) {
+		var name string
+		var config interface{}
+
+		switch *authenticator.Name {
+		case gatewayv2alpha1.OAUTH:
+			name = oauthAuthenticator
+			config = &introspectionConfig{RequiredScope: option.Scopes}
+		case gatewayv2alpha1.JWT:
+			var jwtConfig gatewayv2alpha1.JWTModeConfig
+			err := json.Unmarshal(authenticator.Config.Raw, &jwtConfig)
+			if err != nil {
+				return nil, err
+			}
+			name = jwtAuthenticator
+			config = jwtAuthenticatorConfig(&jwtConfig, option.Path)
+		default:
+			return nil, fmt.Errorf("%s authenticator is not supported by the access rules", *authenticator.Name)
+		}
+
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, &rulev1alpha1.Handler{
+			Name:   name,
+			Config: &runtime.RawExtension{Raw: raw},
+		})
+	}
+
+	return handlers, nil
+}
+
+// jwtScopes returns the scopes the JWT config requires on the path
+func jwtScopes(config *gatewayv2alpha1.JWTModeConfig, path string) []string {
+	for _, option := range config.Paths {
+		if option.Path == path {
+			return option.Scopes
+		}
+	}
+	return nil
+}
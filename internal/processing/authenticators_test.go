@@ -0,0 +1,127 @@
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerateAuthenticators(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getChainedAPI()
+	option := gatewayv2alpha1.Option{
+		Path:   "/orders",
+		Scopes: []string{"orders:read"},
+	}
+
+	handlers, err := generateAuthenticators(exampleAPI, option)
+	assert.NoError(err)
+
+	assert.Equal(len(handlers), 2)
+	assert.Equal(handlers[0].Name, "jwt")
+	assert.Equal(string(handlers[0].Config.Raw), `{"jwks_urls":["https://dex.kyma.local/keys"],"required_scope":["openid"],"target_audience":["orders"],"trusted_issuers":["https://dex.kyma.local"]}`)
+	assert.Equal(handlers[1].Name, "oauth2_introspection")
+	assert.Equal(string(handlers[1].Config.Raw), `{"required_scope":["orders:read"]}`)
+
+	_, err = generateAuthenticators(exampleAPI, gatewayv2alpha1.Option{Path: "/other"})
+	assert.NoError(err)
+}
+
+func TestAccessRuleConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := accessRuleConfig(getChainedAPI())
+	assert.NoError(err)
+	assert.Equal(len(config.Paths), 1)
+	assert.Equal(config.Paths[0].Path, "/orders")
+
+	passthroughAPI := getOauthAPI()
+	passthrough := gatewayv2alpha1.PASSTHROUGH
+	passthroughAPI.Spec.Auth.Name = &passthrough
+	_, err = accessRuleConfig(passthroughAPI)
+	assert.Error(err)
+}
+
+func TestGenerateChainedAccessRule(t *testing.T) {
+	assert := assert.New(t)
+
+	strategyOauth := &oauth{}
+	rule, err := strategyOauth.generateAccessRule(getChainedAPI(), gatewayv2alpha1.Option{Path: "/orders"}, 1)
+	assert.NoError(err)
+
+	assert.Equal(len(rule.Spec.Authenticators), 2)
+	assert.Equal(rule.Spec.Authenticators[0].Name, "jwt")
+	assert.Equal(rule.Spec.Authenticators[1].Name, "oauth2_introspection")
+}
+
+func TestChainedAccessRuleRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	scheme := runtime.NewScheme()
+	assert.NoError(rulev1alpha1.AddToScheme(scheme))
+	c := fake.NewFakeClientWithScheme(scheme)
+	recorder := record.NewFakeRecorder(10)
+
+	exampleAPI := getChainedAPI()
+	option := gatewayv2alpha1.Option{Path: "/orders", Scopes: []string{"orders:read"}}
+
+	strategyOauth := &oauth{Client: c, Recorder: recorder}
+	assert.NoError(strategyOauth.processAccessRule(context.TODO(), exampleAPI, option, 0))
+	assert.Len(drainRecorder(recorder), 1)
+	exampleAPI.Status.GateStatus = &gatewayv2alpha1.GatewayResourceStatus{Code: gatewayv2alpha1.STATUS_OK}
+
+	// The API server returns the handler configs with sorted keys
+	var stored rulev1alpha1.Rule
+	assert.NoError(c.Get(context.TODO(), client.ObjectKey{Namespace: apiNamespace, Name: accessRuleName(exampleAPI, 0)}, &stored))
+	for _, handler := range stored.Spec.Authenticators {
+		var config map[string]interface{}
+		assert.NoError(json.Unmarshal(handler.Config.Raw, &config))
+		sorted, err := json.Marshal(config)
+		assert.NoError(err)
+		assert.Equal(string(handler.Config.Raw), string(sorted))
+	}
+
+	assert.NoError(strategyOauth.processAccessRule(context.TODO(), exampleAPI, option, 0))
+	assert.Len(drainRecorder(recorder), 0)
+}
+
+// getChainedAPI returns a Gate trying a JWT before an OAUTH token
+func getChainedAPI() *gatewayv2alpha1.Gate {
+	api := getOauthAPI()
+	jwt := gatewayv2alpha1.JWT
+	oauth := gatewayv2alpha1.OAUTH
+	api.Spec.Auth = &gatewayv2alpha1.AuthStrategy{
+		Name: &jwt,
+		Config: &runtime.RawExtension{
+			Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","audiences":["orders"],"paths":[{"path":"/orders","scopes":["openid"]}]}`),
+		},
+		Authenticators: []gatewayv2alpha1.Authenticator{
+			{
+				Name:   &oauth,
+				Config: &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/orders","scopes":["orders:read"]}]}`)},
+			},
+		},
+	}
+	return api
+}
+
+func drainRecorder(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
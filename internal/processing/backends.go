@@ -0,0 +1,147 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// processBackends creates a DestinationRule defining the subsets of every service the backends of the Gate split
+// the traffic between
+func processBackends(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate) error {
+	for _, serviceName := range subsetServices(api) {
+		err := processSubsetRule(ctx, c, recorder, api, serviceName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func processSubsetRule(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate, serviceName string) error {
+	var destinationRule istiov1alpha3.DestinationRule
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: subsetRuleName(api, serviceName)}
+
+	err := c.Get(ctx, namespacedName, &destinationRule)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return createGenerated(ctx, c, recorder, api, generateSubsetRule(api, serviceName), "DestinationRule")
+		}
+		return err
+	}
+
+	desired := destinationRule.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *generateSubsetRuleSpec(api, serviceName)
+
+	return updateGenerated(ctx, c, recorder, api, &destinationRule, desired, "DestinationRule")
+}
+
+func generateSubsetRule(api *gatewayv2alpha1.Gate, serviceName string) *istiov1alpha3.DestinationRule {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            subsetRuleName(api, serviceName),
+		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: []k8sMeta.OwnerReference{*generateOwnerRef(api)},
+	}
+
+	return &istiov1alpha3.DestinationRule{
+		ObjectMeta: objectMeta,
+		Spec:       *generateSubsetRuleSpec(api, serviceName),
+	}
+}
+
+func generateSubsetRuleSpec(api *gatewayv2alpha1.Gate, serviceName string) *istiov1alpha3.DestinationRuleSpec {
+	var subsets []istiov1alpha3.Subset
+	defined := map[string]bool{}
+
+	for _, backend := range api.Spec.Backends {
+		if backend.Subset == "" || backendServiceName(api, backend) != serviceName || defined[backend.Subset] {
+			continue
+		}
+		defined[backend.Subset] = true
+		subsets = append(subsets, istiov1alpha3.Subset{
+			Name:   backend.Subset,
+			Labels: backend.Labels,
+		})
+	}
+
+	return &istiov1alpha3.DestinationRuleSpec{
+		Host:    clusterLocalHost(serviceName, api.ObjectMeta.Namespace),
+		Subsets: subsets,
+	}
+}
+
+// generateDefaultDestinations returns the destinations of the requests not matched by the routes of the Gate, which
+// are either split between its backends or forwarded to its service
+func generateDefaultDestinations(api *gatewayv2alpha1.Gate) []istiov1alpha3.HTTPRouteDestination {
+	if len(api.Spec.Backends) == 0 {
+		return []istiov1alpha3.HTTPRouteDestination{
+			{
+				Destination: istiov1alpha3.Destination{
+					Host: defaultServiceHost(api),
+					Port: istiov1alpha3.PortSelector{
+						Number: uint32(*api.Spec.Service.Port),
+					},
+				},
+			},
+		}
+	}
+
+	var destinations []istiov1alpha3.HTTPRouteDestination
+	for _, backend := range api.Spec.Backends {
+		port := api.Spec.Service.Port
+		if backend.Port != nil {
+			port = backend.Port
+		}
+
+		destinations = append(destinations, istiov1alpha3.HTTPRouteDestination{
+			Destination: istiov1alpha3.Destination{
+				Host:   clusterLocalHost(backendServiceName(api, backend), api.ObjectMeta.Namespace),
+				Subset: backend.Subset,
+				Port: istiov1alpha3.PortSelector{
+					Number: uint32(*port),
+				},
+			},
+			Weight: int(backend.Weight),
+		})
+	}
+	return destinations
+}
+
+// subsetServices returns the sorted names of the services whose subsets are referenced by the backends
+func subsetServices(api *gatewayv2alpha1.Gate) []string {
+	var names []string
+	found := map[string]bool{}
+
+	for _, backend := range api.Spec.Backends {
+		name := backendServiceName(api, backend)
+		if backend.Subset == "" || found[name] {
+			continue
+		}
+		found[name] = true
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func backendServiceName(api *gatewayv2alpha1.Gate, backend gatewayv2alpha1.Backend) string {
+	if backend.Name != nil && *backend.Name != "" {
+		return *backend.Name
+	}
+	return *api.Spec.Service.Name
+}
+
+func subsetRuleName(api *gatewayv2alpha1.Gate, serviceName string) string {
+	return fmt.Sprintf("%s-%s-subsets", api.ObjectMeta.Name, serviceName)
+}
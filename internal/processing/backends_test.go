@@ -0,0 +1,48 @@
+package processing
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateBackends(t *testing.T) {
+	assert := assert.New(t)
+
+	legacyName := "legacy-orders"
+	var legacyPort int32 = 9090
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Backends = []gatewayv2alpha1.Backend{
+		{Subset: "v1", Labels: map[string]string{"version": "v1"}, Weight: 80},
+		{Subset: "v2", Labels: map[string]string{"version": "v2"}, Weight: 10},
+		{Name: &legacyName, Port: &legacyPort, Weight: 10},
+	}
+
+	routes := generateHTTPRoutes(exampleAPI)
+	destinations := routes[len(routes)-1].Route
+	assert.Len(destinations, 3)
+	assert.Equal(destinations[0].Destination.Host, serviceName+"."+apiNamespace+".svc.cluster.local")
+	assert.Equal(destinations[0].Destination.Subset, "v1")
+	assert.Equal(destinations[0].Destination.Port.Number, uint32(servicePort))
+	assert.Equal(destinations[0].Weight, 80)
+	assert.Equal(destinations[1].Destination.Subset, "v2")
+	assert.Equal(destinations[1].Weight, 10)
+	assert.Equal(destinations[2].Destination.Host, legacyName+"."+apiNamespace+".svc.cluster.local")
+	assert.Empty(destinations[2].Destination.Subset)
+	assert.Equal(destinations[2].Destination.Port.Number, uint32(legacyPort))
+
+	assert.Equal(subsetServices(exampleAPI), []string{serviceName})
+
+	destinationRule := generateSubsetRule(exampleAPI, serviceName)
+	assert.Equal(destinationRule.ObjectMeta.Name, apiName+"-"+serviceName+"-subsets")
+	assert.Equal(destinationRule.ObjectMeta.Namespace, apiNamespace)
+	assert.Equal(destinationRule.ObjectMeta.OwnerReferences[0].UID, apiUID)
+	assert.Equal(destinationRule.Spec.Host, serviceName+"."+apiNamespace+".svc.cluster.local")
+	assert.Equal(destinationRule.Spec.Subsets, []istiov1alpha3.Subset{
+		{Name: "v1", Labels: map[string]string{"version": "v1"}},
+		{Name: "v2", Labels: map[string]string{"version": "v2"}},
+	})
+}
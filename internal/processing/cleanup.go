@@ -0,0 +1,163 @@
+package processing
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	certmanagerv1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/certmanager/v1alpha1"
+	securityv1beta1 "github.com/kyma-incubator/api-gateway/internal/types/istio/security/v1beta1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CleanupResult holds the number of deleted resources per kind, the RequestAuthentications and AuthorizationPolicies
+// being counted together as policies
+type CleanupResult struct {
+	VirtualServices  int
+	AccessRules      int
+	Policies         int
+	ServiceEntries   int
+	DestinationRules int
+	EnvoyFilters     int
+	Gateways         int
+	Certificates     int
+	Secrets          int
+}
+
+type cleaner struct {
+	Client               client.Client
+	Log                  logr.Logger
+	Recorder             record.EventRecorder
+	CertificateNamespace string
+}
+
+// NewCleaner creates the cleaner of the generated resources. The generated Secrets are only looked for in the given
+// certificate namespace, the only namespace the controller may delete Secrets in.
+func NewCleaner(client client.Client, logger logr.Logger, recorder record.EventRecorder, certificateNamespace string) *cleaner {
+	return &cleaner{
+		Client:               client,
+		Log:                  logger,
+		Recorder:             recorder,
+		CertificateNamespace: certificateNamespace,
+	}
+}
+
+// DeleteOutdated removes the resources generated for the Gate which were not produced by its current generation,
+// e.g. the access rules left behind after switching from OAUTH to PASSTHROUGH
+func (c *cleaner) DeleteOutdated(ctx context.Context, api *gatewayv2alpha1.Gate) (*CleanupResult, error) {
+	generation := strconv.FormatInt(api.ObjectMeta.Generation, 10)
+
+	return c.deleteGenerated(ctx, api, func(obj k8sMeta.Object) bool {
+		return obj.GetLabels()[gateGenerationLabel] != generation
+	})
+}
+
+// DeleteAll removes all the resources generated for the Gate regardless of their namespace
+func (c *cleaner) DeleteAll(ctx context.Context, api *gatewayv2alpha1.Gate) (*CleanupResult, error) {
+	return c.deleteGenerated(ctx, api, func(obj k8sMeta.Object) bool {
+		return true
+	})
+}
+
+func (c *cleaner) deleteGenerated(ctx context.Context, api *gatewayv2alpha1.Gate, shouldDelete func(obj k8sMeta.Object) bool) (*CleanupResult, error) {
+	var err error
+	result := &CleanupResult{}
+
+	result.VirtualServices, err = c.deleteMatching(ctx, api, &istiov1alpha3.VirtualServiceList{}, "VirtualService", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	result.AccessRules, err = c.deleteMatching(ctx, api, &rulev1alpha1.RuleList{}, "Rule", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	requestAuthentications, err := c.deleteMatching(ctx, api, &securityv1beta1.RequestAuthenticationList{}, "RequestAuthentication", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	authorizationPolicies, err := c.deleteMatching(ctx, api, &securityv1beta1.AuthorizationPolicyList{}, "AuthorizationPolicy", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	result.Policies = requestAuthentications + authorizationPolicies
+	result.ServiceEntries, err = c.deleteMatching(ctx, api, &istiov1alpha3.ServiceEntryList{}, "ServiceEntry", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	result.DestinationRules, err = c.deleteMatching(ctx, api, &istiov1alpha3.DestinationRuleList{}, "DestinationRule", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	result.EnvoyFilters, err = c.deleteMatching(ctx, api, &istiov1alpha3.EnvoyFilterList{}, "EnvoyFilter", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	result.Gateways, err = c.deleteMatching(ctx, api, &istiov1alpha3.GatewayList{}, "Gateway", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	result.Certificates, err = c.deleteMatching(ctx, api, &certmanagerv1alpha1.CertificateList{}, "Certificate", shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	result.Secrets, err = c.deleteMatching(ctx, api, &corev1.SecretList{}, "Secret", shouldDelete, client.InNamespace(c.CertificateNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// deleteMatching lists the resources labeled as generated for the Gate in all namespaces, unless restricted by the
+// given options, as owner references cannot point to Gates in other namespaces. Kinds whose CRD is not installed, e.g. the cert-manager Certificates,
+// cannot have been generated, so there is nothing to delete.
+func (c *cleaner) deleteMatching(ctx context.Context, api *gatewayv2alpha1.Gate, list runtime.Object, kind string, shouldDelete func(obj k8sMeta.Object) bool, opts ...client.ListOptionFunc) (int, error) {
+	selector := map[string]string{
+		gateNameLabel:      api.ObjectMeta.Name,
+		gateNamespaceLabel: api.ObjectMeta.Namespace,
+	}
+
+	err := c.Client.List(ctx, list, append(opts, client.MatchingLabels(selector))...)
+	if meta.IsNoMatchError(err) || apierrs.IsNotFound(err) {
+		c.Log.Info("Kind not installed, skipping its cleanup", "kind", kind)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, item := range items {
+		obj, err := meta.Accessor(item)
+		if err != nil {
+			return deleted, err
+		}
+		if !shouldDelete(obj) {
+			continue
+		}
+
+		c.Log.Info("Deleting generated resource", "name", obj.GetName(), "namespace", obj.GetNamespace())
+		err = c.Client.Delete(ctx, item)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return deleted, err
+		}
+		recordChange(c.Recorder, api, item, reasonDeleted, kind, "deleted")
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,23 @@
+package processing
+
+import (
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+)
+
+// generateCorsPolicy translates the CORS policy of the Gate, which is applied to every HTTP route of its VirtualService
+func generateCorsPolicy(api *gatewayv2alpha1.Gate) *istiov1alpha3.CorsPolicy {
+	cors := api.Spec.Cors
+	if cors == nil {
+		return nil
+	}
+
+	return &istiov1alpha3.CorsPolicy{
+		AllowOrigin:      cors.AllowOrigins,
+		AllowMethods:     cors.AllowMethods,
+		AllowHeaders:     cors.AllowHeaders,
+		ExposeHeaders:    cors.ExposeHeaders,
+		MaxAge:           cors.MaxAge,
+		AllowCredentials: cors.AllowCredentials != nil && *cors.AllowCredentials,
+	}
+}
@@ -0,0 +1,37 @@
+package processing
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCorsPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	assert.Nil(generateCorsPolicy(exampleAPI))
+
+	allowCredentials := true
+	exampleAPI.Spec.Cors = &gatewayv2alpha1.CorsPolicy{
+		AllowOrigins:     []string{"https://shop.kyma.local"},
+		AllowMethods:     []string{"GET", "POST"},
+		AllowHeaders:     []string{"Authorization"},
+		ExposeHeaders:    []string{"X-Request-Id"},
+		MaxAge:           "24h",
+		AllowCredentials: &allowCredentials,
+	}
+
+	routes := generateHTTPRoutes(exampleAPI)
+	corsPolicy := routes[len(routes)-1].CorsPolicy
+	assert.Equal(corsPolicy.AllowOrigin, []string{"https://shop.kyma.local"})
+	assert.Equal(corsPolicy.AllowMethods, []string{"GET", "POST"})
+	assert.Equal(corsPolicy.AllowHeaders, []string{"Authorization"})
+	assert.Equal(corsPolicy.ExposeHeaders, []string{"X-Request-Id"})
+	assert.Equal(corsPolicy.MaxAge, "24h")
+	assert.True(corsPolicy.AllowCredentials)
+
+	vs := (&oauth{}).generateVirtualService(exampleAPI, nil)
+	assert.Equal(vs.Spec.HTTP[0].CorsPolicy, corsPolicy)
+}
@@ -0,0 +1,148 @@
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	reasonCreated = "Created"
+	reasonUpdated = "Updated"
+	reasonDeleted = "Deleted"
+	reasonDrifted = "Drifted"
+)
+
+// createGenerated creates a missing generated resource. A resource missing after the Gate was already processed
+// successfully in its current generation was deleted by someone else, which is recorded as drift.
+func createGenerated(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate, desired runtime.Object, kind string) error {
+	if wasProcessed(api) {
+		recordDrift(recorder, api, desired, kind, "deleted")
+	}
+
+	err := c.Create(ctx, desired)
+	if err != nil {
+		return err
+	}
+	recordChange(recorder, api, desired, reasonCreated, kind, "created")
+	return nil
+}
+
+// updateGenerated updates a generated resource unless it already is in its desired state. A resource generated for
+// the current generation of the Gate which no longer matches it was modified by someone else, which is recorded
+// as drift.
+func updateGenerated(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate, current, desired runtime.Object, kind string) error {
+	currentFields, err := ownedFields(current)
+	if err != nil {
+		return err
+	}
+	desiredFields, err := ownedFields(desired)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(currentFields, desiredFields) {
+		return nil
+	}
+
+	obj, err := meta.Accessor(current)
+	if err != nil {
+		return err
+	}
+	if wasProcessed(api) && obj.GetLabels()[gateGenerationLabel] == strconv.FormatInt(api.Generation, 10) {
+		recordDrift(recorder, api, desired, kind, "modified")
+	}
+
+	err = c.Update(ctx, desired)
+	if err != nil {
+		return err
+	}
+	recordChange(recorder, api, desired, reasonUpdated, kind, "updated")
+	return nil
+}
+
+// ownedFields returns the fields of the generated resource set by the controller, i.e. everything but its metadata
+// and status, along with its labels and owner references. The fields are decoded from JSON and stripped of their
+// empty values, so that the normalization of the API server, e.g. of the key order of raw configs or of empty
+// lists, is not mistaken for drift.
+func ownedFields(resource runtime.Object) (map[string]interface{}, error) {
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(raw, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, _ := fields["metadata"].(map[string]interface{})
+	fields["labels"] = metadata["labels"]
+	fields["ownerReferences"] = metadata["ownerReferences"]
+	delete(fields, "metadata")
+	delete(fields, "status")
+	delete(fields, "apiVersion")
+	delete(fields, "kind")
+
+	pruned, _ := pruneEmpty(fields).(map[string]interface{})
+	return pruned, nil
+}
+
+// pruneEmpty removes the nulls, empty lists and empty objects from the decoded JSON value
+func pruneEmpty(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		pruned := map[string]interface{}{}
+		for key, item := range typed {
+			if item = pruneEmpty(item); item != nil {
+				pruned[key] = item
+			}
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		return pruned
+	case []interface{}:
+		if len(typed) == 0 {
+			return nil
+		}
+		pruned := make([]interface{}, len(typed))
+		for i, item := range typed {
+			pruned[i] = pruneEmpty(item)
+		}
+		return pruned
+	default:
+		return value
+	}
+}
+
+func wasProcessed(api *gatewayv2alpha1.Gate) bool {
+	return api.Status.ObservedGeneration == api.Generation && api.Status.GateStatus != nil && api.Status.GateStatus.Code == gatewayv2alpha1.STATUS_OK
+}
+
+// recordChange records the change of a generated resource in the events of the Gate and in the metrics
+func recordChange(recorder record.EventRecorder, api *gatewayv2alpha1.Gate, resource runtime.Object, reason, kind, change string) {
+	metrics.GeneratedResourcesTotal.WithLabelValues(kind, change).Inc()
+
+	obj, err := meta.Accessor(resource)
+	if err != nil {
+		return
+	}
+	recorder.Eventf(api, corev1.EventTypeNormal, reason, "%s %s/%s %s", kind, obj.GetNamespace(), obj.GetName(), change)
+}
+
+func recordDrift(recorder record.EventRecorder, api *gatewayv2alpha1.Gate, resource runtime.Object, kind, change string) {
+	obj, err := meta.Accessor(resource)
+	if err != nil {
+		return
+	}
+	recorder.Eventf(api, corev1.EventTypeWarning, reasonDrifted, "%s %s/%s was %s outside of the Gate, restoring it", kind, obj.GetNamespace(), obj.GetName(), change)
+}
@@ -0,0 +1,48 @@
+package processing
+
+import (
+	"testing"
+
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestOwnedFields(t *testing.T) {
+	assert := assert.New(t)
+
+	desired := &rulev1alpha1.Rule{
+		ObjectMeta: k8sMeta.ObjectMeta{Name: "rule", Labels: map[string]string{gateNameLabel: apiName}},
+		Spec: rulev1alpha1.RuleSpec{
+			Match: &rulev1alpha1.Match{URL: "<http|https>://foo.bar</foo>", Methods: []string{}},
+			Authenticators: []*rulev1alpha1.Handler{
+				{Name: "jwt", Config: &runtime.RawExtension{Raw: []byte(`{"jwks_urls":["https://dex.kyma.local/keys"],"required_scope":["read"],"target_audience":["foo"]}`)}},
+			},
+		},
+	}
+
+	normalized := desired.DeepCopy()
+	normalized.ObjectMeta.ResourceVersion = "42"
+	normalized.ObjectMeta.UID = apiUID
+	normalized.Spec.Match.Methods = nil
+	normalized.Spec.Authenticators[0].Config.Raw = []byte(`{"jwks_urls": ["https://dex.kyma.local/keys"], "target_audience": ["foo"], "required_scope": ["read"]}`)
+
+	desiredFields, err := ownedFields(desired)
+	assert.NoError(err)
+	normalizedFields, err := ownedFields(normalized)
+	assert.NoError(err)
+	assert.Equal(desiredFields, normalizedFields)
+
+	modified := desired.DeepCopy()
+	modified.Spec.Authenticators[0].Config.Raw = []byte(`{"jwks_urls":["https://evil.example.com/keys"]}`)
+	modifiedFields, err := ownedFields(modified)
+	assert.NoError(err)
+	assert.NotEqual(desiredFields, modifiedFields)
+
+	relabeled := desired.DeepCopy()
+	relabeled.ObjectMeta.Labels = nil
+	relabeledFields, err := ownedFields(relabeled)
+	assert.NoError(err)
+	assert.NotEqual(desiredFields, relabeledFields)
+}
@@ -0,0 +1,143 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// processExternalService registers an external service in the mesh with a ServiceEntry and, if requested,
+// originates TLS to it with a DestinationRule. Resources are not created for in-cluster services.
+func processExternalService(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate) error {
+	if !isExternal(api) {
+		return nil
+	}
+
+	err := processServiceEntry(ctx, c, recorder, api)
+	if err != nil {
+		return err
+	}
+
+	if api.Spec.Service.OriginateTLS != nil && *api.Spec.Service.OriginateTLS {
+		return processDestinationRule(ctx, c, recorder, api)
+	}
+	return nil
+}
+
+func isExternal(api *gatewayv2alpha1.Gate) bool {
+	return api.Spec.Service.IsExternal != nil && *api.Spec.Service.IsExternal
+}
+
+func processServiceEntry(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate) error {
+	var serviceEntry istiov1alpha3.ServiceEntry
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: virtualServiceName(api)}
+
+	err := c.Get(ctx, namespacedName, &serviceEntry)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return createGenerated(ctx, c, recorder, api, generateServiceEntry(api), "ServiceEntry")
+		}
+		return err
+	}
+
+	desired := serviceEntry.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *generateServiceEntrySpec(api)
+
+	return updateGenerated(ctx, c, recorder, api, &serviceEntry, desired, "ServiceEntry")
+}
+
+func generateServiceEntry(api *gatewayv2alpha1.Gate) *istiov1alpha3.ServiceEntry {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            virtualServiceName(api),
+		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: []k8sMeta.OwnerReference{*generateOwnerRef(api)},
+	}
+
+	return &istiov1alpha3.ServiceEntry{
+		ObjectMeta: objectMeta,
+		Spec:       *generateServiceEntrySpec(api),
+	}
+}
+
+func generateServiceEntrySpec(api *gatewayv2alpha1.Gate) *istiov1alpha3.ServiceEntrySpec {
+	protocol := "HTTP"
+	if api.Spec.Service.OriginateTLS != nil && *api.Spec.Service.OriginateTLS {
+		protocol = "HTTPS"
+	}
+
+	return &istiov1alpha3.ServiceEntrySpec{
+		Hosts: []string{*api.Spec.Service.Name},
+		Ports: []istiov1alpha3.Port{
+			{
+				Number:   int(*api.Spec.Service.Port),
+				Protocol: istiov1alpha3.PortProtocol(protocol),
+				Name:     fmt.Sprintf("%s-%d", strings.ToLower(protocol), *api.Spec.Service.Port),
+			},
+		},
+		Location:   istiov1alpha3.LocationMeshExternal,
+		Resolution: istiov1alpha3.ResolutionDNS,
+	}
+}
+
+func processDestinationRule(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate) error {
+	var destinationRule istiov1alpha3.DestinationRule
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: virtualServiceName(api)}
+
+	err := c.Get(ctx, namespacedName, &destinationRule)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return createGenerated(ctx, c, recorder, api, generateDestinationRule(api), "DestinationRule")
+		}
+		return err
+	}
+
+	desired := destinationRule.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *generateDestinationRuleSpec(api)
+
+	return updateGenerated(ctx, c, recorder, api, &destinationRule, desired, "DestinationRule")
+}
+
+func generateDestinationRule(api *gatewayv2alpha1.Gate) *istiov1alpha3.DestinationRule {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            virtualServiceName(api),
+		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: []k8sMeta.OwnerReference{*generateOwnerRef(api)},
+	}
+
+	return &istiov1alpha3.DestinationRule{
+		ObjectMeta: objectMeta,
+		Spec:       *generateDestinationRuleSpec(api),
+	}
+}
+
+func generateDestinationRuleSpec(api *gatewayv2alpha1.Gate) *istiov1alpha3.DestinationRuleSpec {
+	return &istiov1alpha3.DestinationRuleSpec{
+		Host: *api.Spec.Service.Name,
+		TrafficPolicy: &istiov1alpha3.TrafficPolicy{
+			PortLevelSettings: []istiov1alpha3.PortTrafficPolicy{
+				{
+					Port: istiov1alpha3.PortSelector{
+						Number: uint32(*api.Spec.Service.Port),
+					},
+					TLS: &istiov1alpha3.TLSSettings{
+						Mode: istiov1alpha3.TLSmodeSimple,
+						Sni:  *api.Spec.Service.Name,
+					},
+				},
+			},
+		},
+	}
+}
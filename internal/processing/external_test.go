@@ -0,0 +1,49 @@
+package processing
+
+import (
+	"testing"
+
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateExternalServiceResources(t *testing.T) {
+	assert := assert.New(t)
+
+	externalName := "api.imgur.com"
+	var externalPort int32 = 443
+	isExternal := true
+	originateTLS := true
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Service.Name = &externalName
+	exampleAPI.Spec.Service.Port = &externalPort
+	exampleAPI.Spec.Service.IsExternal = &isExternal
+	exampleAPI.Spec.Service.OriginateTLS = &originateTLS
+
+	serviceEntry := generateServiceEntry(exampleAPI)
+	assert.Equal(serviceEntry.ObjectMeta.Name, apiName+"-"+externalName)
+	assert.Equal(serviceEntry.ObjectMeta.Namespace, apiNamespace)
+	assert.Equal(serviceEntry.Spec.Hosts, []string{externalName})
+	assert.Equal(serviceEntry.Spec.Ports[0].Number, int(externalPort))
+	assert.Equal(serviceEntry.Spec.Ports[0].Protocol, istiov1alpha3.ProtocolHTTPS)
+	assert.Equal(serviceEntry.Spec.Ports[0].Name, "https-443")
+	assert.Equal(serviceEntry.Spec.Location, istiov1alpha3.LocationMeshExternal)
+	assert.Equal(serviceEntry.Spec.Resolution, istiov1alpha3.ResolutionDNS)
+
+	destinationRule := generateDestinationRule(exampleAPI)
+	assert.Equal(destinationRule.Spec.Host, externalName)
+	assert.Equal(destinationRule.Spec.TrafficPolicy.PortLevelSettings[0].Port.Number, uint32(externalPort))
+	assert.Equal(destinationRule.Spec.TrafficPolicy.PortLevelSettings[0].TLS.Mode, istiov1alpha3.TLSmodeSimple)
+
+	vs := (&passthrough{}).generateVirtualService(exampleAPI)
+	assert.Equal(vs.Spec.HTTP[0].Route[0].Destination.Host, externalName)
+	assert.Equal(vs.Spec.HTTP[0].Route[0].Destination.Port.Number, uint32(externalPort))
+
+	plainPort := int32(80)
+	exampleAPI.Spec.Service.Port = &plainPort
+	exampleAPI.Spec.Service.OriginateTLS = nil
+	serviceEntry = generateServiceEntry(exampleAPI)
+	assert.Equal(serviceEntry.Spec.Ports[0].Protocol, istiov1alpha3.ProtocolHTTP)
+	assert.Equal(serviceEntry.Spec.Ports[0].Name, "http-80")
+}
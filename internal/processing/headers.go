@@ -0,0 +1,66 @@
+package processing
+
+import (
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+)
+
+func generateRewrite(rewrite *gatewayv2alpha1.Rewrite) *istiov1alpha3.HTTPRewrite {
+	if rewrite == nil {
+		return nil
+	}
+
+	return &istiov1alpha3.HTTPRewrite{
+		URI:       rewrite.URI,
+		Authority: rewrite.Authority,
+	}
+}
+
+// generateHeaders translates the headers of the Gate extended by the headers of the route. The values of the route
+// take precedence over the ones of the Gate.
+func generateHeaders(gate, route *gatewayv2alpha1.Headers) *istiov1alpha3.Headers {
+	if gate == nil && route == nil {
+		return nil
+	}
+	if gate == nil {
+		gate = &gatewayv2alpha1.Headers{}
+	}
+	if route == nil {
+		route = &gatewayv2alpha1.Headers{}
+	}
+
+	return &istiov1alpha3.Headers{
+		Request:  mergeHeaderOperations(gate.Request, route.Request),
+		Response: mergeHeaderOperations(gate.Response, route.Response),
+	}
+}
+
+func mergeHeaderOperations(gate, route *gatewayv2alpha1.HeaderOperations) *istiov1alpha3.HeaderOperations {
+	if gate == nil && route == nil {
+		return nil
+	}
+
+	merged := &istiov1alpha3.HeaderOperations{}
+	for _, operations := range []*gatewayv2alpha1.HeaderOperations{gate, route} {
+		if operations == nil {
+			continue
+		}
+		merged.Set = mergeHeaderValues(merged.Set, operations.Set)
+		merged.Add = mergeHeaderValues(merged.Add, operations.Add)
+		merged.Remove = append(merged.Remove, operations.Remove...)
+	}
+	return merged
+}
+
+func mergeHeaderValues(merged, values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return merged
+	}
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	for name, value := range values {
+		merged[name] = value
+	}
+	return merged
+}
@@ -0,0 +1,62 @@
+package processing
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateHTTPRoutesRewriteAndHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	ordersName := "orders"
+	var ordersPort int32 = 8081
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Headers = &gatewayv2alpha1.Headers{
+		Request: &gatewayv2alpha1.HeaderOperations{
+			Set:    map[string]string{"x-gateway": "kyma", "x-forwarded-prefix": "/"},
+			Remove: []string{"cookie"},
+		},
+	}
+	exampleAPI.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/api/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: &ordersName, Port: &ordersPort},
+			Rewrite: &gatewayv2alpha1.Rewrite{URI: "/", Authority: "orders.internal"},
+			Headers: &gatewayv2alpha1.Headers{
+				Request: &gatewayv2alpha1.HeaderOperations{
+					Set: map[string]string{"x-forwarded-prefix": "/api/orders"},
+				},
+				Response: &gatewayv2alpha1.HeaderOperations{
+					Remove: []string{"x-powered-by"},
+				},
+			},
+			Traffic: &gatewayv2alpha1.TrafficPolicy{
+				Retries: &gatewayv2alpha1.Retries{Attempts: 2, PerTryTimeout: "1s", RetryOn: "5xx"},
+			},
+		},
+	}
+
+	routes := generateHTTPRoutes(exampleAPI)
+	assert.Len(routes, 2)
+
+	orders := routes[0]
+	assert.Equal(orders.Rewrite.URI, "/")
+	assert.Equal(orders.Rewrite.Authority, "orders.internal")
+	assert.Equal(orders.Headers.Request.Set, map[string]string{
+		"x-gateway":          "kyma",
+		"x-forwarded-prefix": "/api/orders",
+	})
+	assert.Equal(orders.Retries.RetryOn, "5xx")
+	assert.Equal(orders.Headers.Request.Remove, []string{"cookie"})
+	assert.Equal(orders.Headers.Response.Remove, []string{"x-powered-by"})
+
+	defaultRoute := routes[1]
+	assert.Nil(defaultRoute.Rewrite)
+	assert.Equal(defaultRoute.Headers.Request.Set, map[string]string{"x-gateway": "kyma", "x-forwarded-prefix": "/"})
+	assert.Nil(defaultRoute.Headers.Response)
+
+	assert.Equal(exampleAPI.Spec.Headers.Request.Set["x-forwarded-prefix"], "/")
+}
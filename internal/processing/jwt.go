@@ -0,0 +1,233 @@
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	securityv1beta1 "github.com/kyma-incubator/api-gateway/internal/types/istio/security/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// scopeClaim is the claim of the token listing its scopes
+const scopeClaim = "scope"
+
+type jwt struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// Process verifies the tokens in the sidecars of the pods of the service of the Gate. The RequestAuthentication
+// rejects the invalid tokens, and the AuthorizationPolicy rejects the requests without a token, but on the excluded
+// paths. Both select the pods by the selector of the service.
+func (j *jwt) Process(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	var jwtConfig gatewayv2alpha1.JWTModeConfig
+
+	err := json.Unmarshal(api.Spec.Auth.Config.Raw, &jwtConfig)
+	if err != nil {
+		return err
+	}
+
+	selector, err := j.getWorkloadSelector(ctx, api)
+	if err != nil {
+		return err
+	}
+
+	err = j.processRequestAuthentication(ctx, api, generateRequestAuthenticationSpec(selector, &jwtConfig))
+	if err != nil {
+		return err
+	}
+
+	err = j.processAuthorizationPolicy(ctx, api, generateAuthorizationPolicySpec(selector, &jwtConfig))
+	if err != nil {
+		return err
+	}
+
+	// The token is verified by the service sidecar, so the traffic is routed straight to the service
+	return (&passthrough{Client: j.Client, Recorder: j.Recorder}).Process(ctx, api)
+}
+
+// getWorkloadSelector returns the selector of the pods of the service of the Gate
+func (j *jwt) getWorkloadSelector(ctx context.Context, api *gatewayv2alpha1.Gate) (*securityv1beta1.WorkloadSelector, error) {
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: *api.Spec.Service.Name}
+	var service corev1.Service
+
+	err := j.Client.Get(ctx, namespacedName, &service)
+	if err != nil {
+		return nil, err
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service %s/%s does not select its pods, the JWT strategy cannot verify the tokens on them", service.Namespace, service.Name)
+	}
+
+	return &securityv1beta1.WorkloadSelector{MatchLabels: service.Spec.Selector}, nil
+}
+
+func (j *jwt) processRequestAuthentication(ctx context.Context, api *gatewayv2alpha1.Gate, spec *securityv1beta1.RequestAuthenticationSpec) error {
+	var requestAuthentication securityv1beta1.RequestAuthentication
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: policyName(api)}
+
+	err := j.Client.Get(ctx, namespacedName, &requestAuthentication)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			desired := &securityv1beta1.RequestAuthentication{ObjectMeta: generatePolicyObjectMeta(api), Spec: *spec}
+			return createGenerated(ctx, j.Client, j.Recorder, api, desired, "RequestAuthentication")
+		}
+		return err
+	}
+
+	desired := requestAuthentication.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *spec
+
+	return updateGenerated(ctx, j.Client, j.Recorder, api, &requestAuthentication, desired, "RequestAuthentication")
+}
+
+func (j *jwt) processAuthorizationPolicy(ctx context.Context, api *gatewayv2alpha1.Gate, spec *securityv1beta1.AuthorizationPolicySpec) error {
+	var authorizationPolicy securityv1beta1.AuthorizationPolicy
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: policyName(api)}
+
+	err := j.Client.Get(ctx, namespacedName, &authorizationPolicy)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			desired := &securityv1beta1.AuthorizationPolicy{ObjectMeta: generatePolicyObjectMeta(api), Spec: *spec}
+			return createGenerated(ctx, j.Client, j.Recorder, api, desired, "AuthorizationPolicy")
+		}
+		return err
+	}
+
+	desired := authorizationPolicy.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *spec
+
+	return updateGenerated(ctx, j.Client, j.Recorder, api, &authorizationPolicy, desired, "AuthorizationPolicy")
+}
+
+func generatePolicyObjectMeta(api *gatewayv2alpha1.Gate) k8sMeta.ObjectMeta {
+	return k8sMeta.ObjectMeta{
+		Name:            policyName(api),
+		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: []k8sMeta.OwnerReference{*generateOwnerRef(api)},
+	}
+}
+
+func generateRequestAuthenticationSpec(selector *securityv1beta1.WorkloadSelector, config *gatewayv2alpha1.JWTModeConfig) *securityv1beta1.RequestAuthenticationSpec {
+	return &securityv1beta1.RequestAuthenticationSpec{
+		Selector: selector,
+		JWTRules: []securityv1beta1.JWTRule{
+			{
+				Issuer:    config.Issuer,
+				JWKSURI:   config.JWKSURI,
+				Audiences: config.Audiences,
+			},
+		},
+	}
+}
+
+// generateAuthorizationPolicySpec allows the requests with a valid token, the only ones with a request principal,
+// and the requests to the excluded paths. On the paths requiring scopes or claims, the token must also grant them.
+func generateAuthorizationPolicySpec(selector *securityv1beta1.WorkloadSelector, config *gatewayv2alpha1.JWTModeConfig) *securityv1beta1.AuthorizationPolicySpec {
+	validToken := []securityv1beta1.RuleFrom{
+		{Source: &securityv1beta1.Source{RequestPrincipals: []string{"*"}}},
+	}
+
+	var restrictedPaths []string
+	var pathRules []securityv1beta1.Rule
+	for _, option := range config.Paths {
+		conditions := generateClaimConditions(option)
+		if len(conditions) == 0 {
+			continue
+		}
+		restrictedPaths = append(restrictedPaths, option.Path)
+		pathRules = append(pathRules, securityv1beta1.Rule{
+			From: validToken,
+			To: []securityv1beta1.RuleTo{
+				{Operation: &securityv1beta1.Operation{Paths: []string{option.Path}}},
+			},
+			When: conditions,
+		})
+	}
+
+	anyPathRule := securityv1beta1.Rule{From: validToken}
+	if len(restrictedPaths) > 0 {
+		anyPathRule.To = []securityv1beta1.RuleTo{
+			{Operation: &securityv1beta1.Operation{NotPaths: restrictedPaths}},
+		}
+	}
+	rules := append([]securityv1beta1.Rule{anyPathRule}, pathRules...)
+
+	var excludedPaths []string
+	for _, rule := range config.TriggerRules {
+		for _, path := range rule.ExcludedPaths {
+			excludedPaths = append(excludedPaths, authorizationPolicyPath(path))
+		}
+	}
+	if len(excludedPaths) > 0 {
+		rules = append(rules, securityv1beta1.Rule{
+			To: []securityv1beta1.RuleTo{
+				{Operation: &securityv1beta1.Operation{Paths: excludedPaths}},
+			},
+		})
+	}
+
+	return &securityv1beta1.AuthorizationPolicySpec{
+		Selector: selector,
+		Action:   securityv1beta1.AuthorizationPolicyActionAllow,
+		Rules:    rules,
+	}
+}
+
+// generateClaimConditions requires the claims of the path with their values, and the scopes of the path in the scope
+// claim of the token
+func generateClaimConditions(option gatewayv2alpha1.JWTOption) []securityv1beta1.Condition {
+	var claims []string
+	for claim := range option.RequiredClaims {
+		claims = append(claims, claim)
+	}
+	sort.Strings(claims)
+
+	var conditions []securityv1beta1.Condition
+	for _, claim := range claims {
+		conditions = append(conditions, securityv1beta1.Condition{
+			Key:    claimKey(claim),
+			Values: []string{option.RequiredClaims[claim]},
+		})
+	}
+	for _, scope := range option.Scopes {
+		conditions = append(conditions, securityv1beta1.Condition{
+			Key:    claimKey(scopeClaim),
+			Values: []string{scope},
+		})
+	}
+	return conditions
+}
+
+func claimKey(claim string) string {
+	return fmt.Sprintf("request.auth.claims[%s]", claim)
+}
+
+// authorizationPolicyPath returns the path of the AuthorizationPolicy matching like the excluded path. Regexes are
+// rejected by the validation, as the AuthorizationPolicy does not support them.
+func authorizationPolicyPath(path gatewayv2alpha1.StringMatch) string {
+	switch {
+	case path.Prefix != "":
+		return path.Prefix + "*"
+	case path.Suffix != "":
+		return "*" + path.Suffix
+	default:
+		return path.Exact
+	}
+}
+
+func policyName(api *gatewayv2alpha1.Gate) string {
+	return virtualServiceName(api)
+}
@@ -0,0 +1,75 @@
+package processing
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	securityv1beta1 "github.com/kyma-incubator/api-gateway/internal/types/istio/security/v1beta1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := &securityv1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": serviceName}}
+	config := &gatewayv2alpha1.JWTModeConfig{
+		Issuer:    "https://dex.kyma.local",
+		JWKSURI:   "https://dex.kyma.local/keys",
+		Audiences: []string{"foo"},
+		TriggerRules: []gatewayv2alpha1.TriggerRule{
+			{
+				ExcludedPaths: []gatewayv2alpha1.StringMatch{
+					{Exact: "/healthz"},
+					{Prefix: "/docs"},
+				},
+			},
+			{
+				ExcludedPaths: []gatewayv2alpha1.StringMatch{
+					{Suffix: ".css"},
+				},
+			},
+		},
+	}
+
+	objectMeta := generatePolicyObjectMeta(getOauthAPI())
+	assert.Equal(objectMeta.Name, apiName+"-"+serviceName)
+	assert.Equal(objectMeta.Namespace, apiNamespace)
+	assert.Equal(objectMeta.OwnerReferences[0].UID, apiUID)
+
+	authentication := generateRequestAuthenticationSpec(selector, config)
+	assert.Equal(authentication.Selector, selector)
+	assert.Equal(len(authentication.JWTRules), 1)
+	assert.Equal(authentication.JWTRules[0].Issuer, "https://dex.kyma.local")
+	assert.Equal(authentication.JWTRules[0].JWKSURI, "https://dex.kyma.local/keys")
+	assert.Equal(authentication.JWTRules[0].Audiences, []string{"foo"})
+
+	authorization := generateAuthorizationPolicySpec(selector, config)
+	assert.Equal(authorization.Selector, selector)
+	assert.Equal(authorization.Action, securityv1beta1.AuthorizationPolicyActionAllow)
+	assert.Equal(len(authorization.Rules), 2)
+	assert.Equal(authorization.Rules[0].From[0].Source.RequestPrincipals, []string{"*"})
+	assert.Empty(authorization.Rules[0].To)
+	assert.Empty(authorization.Rules[1].From)
+	assert.Equal(authorization.Rules[1].To[0].Operation.Paths, []string{"/healthz", "/docs*", "*.css"})
+
+	config.TriggerRules = nil
+	authorization = generateAuthorizationPolicySpec(selector, config)
+	assert.Equal(len(authorization.Rules), 1)
+
+	config.Paths = []gatewayv2alpha1.JWTOption{
+		{Path: "/public"},
+		{Path: "/orders/*", Scopes: []string{"read", "write"}, RequiredClaims: map[string]string{"team": "orders", "aud": "shop"}},
+	}
+	authorization = generateAuthorizationPolicySpec(selector, config)
+	assert.Equal(len(authorization.Rules), 2)
+	assert.Equal(authorization.Rules[0].From[0].Source.RequestPrincipals, []string{"*"})
+	assert.Equal(authorization.Rules[0].To[0].Operation.NotPaths, []string{"/orders/*"})
+	assert.Equal(authorization.Rules[1].From[0].Source.RequestPrincipals, []string{"*"})
+	assert.Equal(authorization.Rules[1].To[0].Operation.Paths, []string{"/orders/*"})
+	assert.Equal(authorization.Rules[1].When, []securityv1beta1.Condition{
+		{Key: "request.auth.claims[aud]", Values: []string{"shop"}},
+		{Key: "request.auth.claims[team]", Values: []string{"orders"}},
+		{Key: "request.auth.claims[scope]", Values: []string{"read"}},
+		{Key: "request.auth.claims[scope]", Values: []string{"write"}},
+	})
+}
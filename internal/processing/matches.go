@@ -0,0 +1,62 @@
+package processing
+
+import (
+	"fmt"
+	"regexp"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"knative.dev/pkg/apis/istio/common/v1alpha1"
+)
+
+// The query parameter and cookie are matched within the path and cookie headers, as the Istio API vendored here does
+// not support their matches. A header is matched once, so a route matches at most one of each.
+const (
+	pathHeader   = ":path"
+	cookieHeader = "cookie"
+)
+
+// generateHeaderMatches translates the conditions of the route into header matches
+func generateHeaderMatches(conditions *gatewayv2alpha1.RouteConditions) map[string]v1alpha1.StringMatch {
+	if conditions == nil {
+		return nil
+	}
+
+	matches := map[string]v1alpha1.StringMatch{}
+	for name, match := range conditions.Headers {
+		matches[name] = v1alpha1.StringMatch{
+			Exact:  match.Exact,
+			Prefix: match.Prefix,
+			Suffix: match.Suffix,
+			Regex:  match.Regex,
+		}
+	}
+	if param := conditions.QueryParam; param != nil {
+		matches[pathHeader] = v1alpha1.StringMatch{
+			Regex: fmt.Sprintf(`[^?]*\?(?:.*&)?%s=%s(?:&.*)?`, regexp.QuoteMeta(param.Name), valueRegex(param.Value, "[^&]")),
+		}
+	}
+	if cookie := conditions.Cookie; cookie != nil {
+		matches[cookieHeader] = v1alpha1.StringMatch{
+			Regex: fmt.Sprintf(`(?:.*;\s*)?%s=%s(?:;.*)?`, regexp.QuoteMeta(cookie.Name), valueRegex(cookie.Value, "[^;]")),
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches
+}
+
+// valueRegex translates the match of a value delimited by a character outside of the given class into a regex
+func valueRegex(match gatewayv2alpha1.StringMatch, valueClass string) string {
+	switch {
+	case match.Exact != "":
+		return regexp.QuoteMeta(match.Exact)
+	case match.Prefix != "":
+		return regexp.QuoteMeta(match.Prefix) + valueClass + "*"
+	case match.Suffix != "":
+		return valueClass + "*" + regexp.QuoteMeta(match.Suffix)
+	default:
+		return "(?:" + match.Regex + ")"
+	}
+}
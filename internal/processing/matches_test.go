@@ -0,0 +1,59 @@
+package processing
+
+import (
+	"regexp"
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateHTTPRoutesConditions(t *testing.T) {
+	assert := assert.New(t)
+
+	betaName := "orders-beta"
+	var betaPort int32 = 8081
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: &serviceName, Port: &servicePort},
+		},
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: &betaName, Port: &betaPort},
+			Conditions: &gatewayv2alpha1.RouteConditions{
+				Headers:    map[string]gatewayv2alpha1.StringMatch{"x-beta-user": {Exact: "true"}},
+				QueryParam: &gatewayv2alpha1.NamedStringMatch{Name: "variant", Value: gatewayv2alpha1.StringMatch{Exact: "b.1"}},
+				Cookie:     &gatewayv2alpha1.NamedStringMatch{Name: "ab-group", Value: gatewayv2alpha1.StringMatch{Prefix: "beta"}},
+			},
+		},
+	}
+
+	routes := generateHTTPRoutes(exampleAPI)
+	assert.Len(routes, 3)
+
+	beta := routes[0]
+	assert.Equal(beta.Route[0].Destination.Host, betaName+"."+apiNamespace+".svc.cluster.local")
+	assert.Equal(beta.Match[0].URI.Prefix, "/orders")
+	assert.Equal(beta.Match[0].Headers["x-beta-user"].Exact, "true")
+
+	pathRegex := regexp.MustCompile("^(?:" + beta.Match[0].Headers[pathHeader].Regex + ")$")
+	assert.True(pathRegex.MatchString("/orders?variant=b.1"))
+	assert.True(pathRegex.MatchString("/orders/1?page=2&variant=b.1&sort=asc"))
+	assert.False(pathRegex.MatchString("/orders?variant=bx1"))
+	assert.False(pathRegex.MatchString("/orders?novariant=b.1"))
+
+	cookieRegex := regexp.MustCompile("^(?:" + beta.Match[0].Headers[cookieHeader].Regex + ")$")
+	assert.True(cookieRegex.MatchString("ab-group=beta-2"))
+	assert.True(cookieRegex.MatchString("session=abc; ab-group=beta; theme=dark"))
+	assert.False(cookieRegex.MatchString("ab-group=stable"))
+	assert.False(cookieRegex.MatchString("xab-group=beta"))
+
+	assert.Nil(routes[1].Match[0].Headers)
+	assert.Equal(routes[1].Route[0].Destination.Host, serviceName+"."+apiNamespace+".svc.cluster.local")
+
+	host, _ := upstreamFor(exampleAPI, "/orders/1")
+	assert.Equal(host, serviceName+"."+apiNamespace+".svc.cluster.local")
+}
@@ -0,0 +1,89 @@
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis/istio/common/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// clientCertHeader is set by the gateway to the details of the verified client certificate, e.g.
+	// Hash=...;Subject="CN=partner";URI=spiffe://partner;DNS=partner.example.com
+	clientCertHeader      = "x-forwarded-client-cert"
+	defaultIdentityHeader = "x-client-subject"
+	defaultCABundleKey    = "ca.crt"
+	clientSubjectVariable = "%DOWNSTREAM_PEER_SUBJECT%"
+)
+
+type mtls struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// Process routes the requests of the clients authenticated by the gateway straight to the services. The gateway
+// verifies the client certificates against the CA bundle synchronized by the TLS processor.
+func (m *mtls) Process(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	var mtlsConfig gatewayv2alpha1.MTLSModeConfig
+
+	err := json.Unmarshal(api.Spec.Auth.Config.Raw, &mtlsConfig)
+	if err != nil {
+		return err
+	}
+
+	return (&passthrough{Client: m.Client, Recorder: m.Recorder, clientCertificate: &mtlsConfig}).Process(ctx, api)
+}
+
+// applyClientCertificatePolicy restricts the HTTP routes to the clients whose certificate subject or SANs are allowed,
+// and forwards the subject of the client certificate to the services. The requests of the other clients are not
+// matched by any route and rejected by the gateway.
+func applyClientCertificatePolicy(spec *istiov1alpha3.VirtualServiceSpec, config *gatewayv2alpha1.MTLSModeConfig) {
+	identityHeader := config.IdentityHeader
+	if identityHeader == "" {
+		identityHeader = defaultIdentityHeader
+	}
+	clientMatch := clientCertificateRegex(config)
+
+	for i := range spec.HTTP {
+		httpRoute := &spec.HTTP[i]
+		setRequestHeader(httpRoute, identityHeader, clientSubjectVariable)
+		if clientMatch == "" {
+			continue
+		}
+		for j := range httpRoute.Match {
+			if httpRoute.Match[j].Headers == nil {
+				httpRoute.Match[j].Headers = map[string]v1alpha1.StringMatch{}
+			}
+			httpRoute.Match[j].Headers[clientCertHeader] = v1alpha1.StringMatch{Regex: clientMatch}
+		}
+	}
+}
+
+// clientCertificateRegex matches the client certificate details with any of the allowed subjects or URI and DNS SANs
+func clientCertificateRegex(config *gatewayv2alpha1.MTLSModeConfig) string {
+	var alternatives []string
+	for _, subject := range config.Subjects {
+		alternatives = append(alternatives, fmt.Sprintf(`Subject="(?:%s)"`, subject))
+	}
+	for _, san := range config.SubjectAltNames {
+		alternatives = append(alternatives, fmt.Sprintf(`(?:URI|DNS)=(?:%s)`, san))
+	}
+	if len(alternatives) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`(?:.*;)?(?:%s)(?:;.*)?`, strings.Join(alternatives, "|"))
+}
+
+// caBundleKey returns the key of the CA certificates in the secret referenced by the config
+func caBundleKey(config *gatewayv2alpha1.MTLSModeConfig) string {
+	if config.CABundle.Key != "" {
+		return config.CABundle.Key
+	}
+	return defaultCABundleKey
+}
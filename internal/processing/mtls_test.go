@@ -0,0 +1,59 @@
+package processing
+
+import (
+	"regexp"
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateVirtualServiceWithClientCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeMutual}
+	exampleAPI.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: &serviceName, Port: &servicePort},
+		},
+	}
+	config := &gatewayv2alpha1.MTLSModeConfig{
+		CABundle:        &gatewayv2alpha1.CABundleReference{SecretName: "partner-ca"},
+		Subjects:        []string{"CN=partner,O=Example"},
+		SubjectAltNames: []string{`spiffe://partner\.example\.com/.*`},
+	}
+
+	strategy := &passthrough{clientCertificate: config}
+	spec := strategy.generateVirtualServiceSpec(exampleAPI)
+
+	assert.Len(spec.HTTP, 2)
+	for _, httpRoute := range spec.HTTP {
+		assert.Equal(httpRoute.Headers.Request.Set["x-client-subject"], "%DOWNSTREAM_PEER_SUBJECT%")
+		assert.NotEmpty(httpRoute.Match[0].Headers[clientCertHeader].Regex)
+	}
+
+	clientMatch := regexp.MustCompile("^" + spec.HTTP[0].Match[0].Headers[clientCertHeader].Regex + "$")
+	assert.True(clientMatch.MatchString(`Hash=abc;Subject="CN=partner,O=Example";URI=`))
+	assert.True(clientMatch.MatchString(`Hash=abc;Subject="CN=other";URI=spiffe://partner.example.com/billing`))
+	assert.False(clientMatch.MatchString(`Hash=abc;Subject="CN=other";URI=spiffe://other.example.com/billing`))
+
+	config.Subjects, config.SubjectAltNames = nil, nil
+	config.IdentityHeader = "x-partner"
+	spec = strategy.generateVirtualServiceSpec(exampleAPI)
+	assert.Empty(spec.HTTP[0].Match[0].Headers)
+	assert.Equal(spec.HTTP[0].Headers.Request.Set["x-partner"], "%DOWNSTREAM_PEER_SUBJECT%")
+}
+
+func TestGenerateCASecret(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeMutual}
+
+	secret := generateCASecret(exampleAPI, apiNamespace, []byte("bundle"))
+	assert.Equal(secret.ObjectMeta.Name, apiNamespace+"-"+apiName+"-tls-cacert")
+	assert.Equal(secret.ObjectMeta.OwnerReferences[0].UID, apiUID)
+	assert.Equal(secret.Data["cacert"], []byte("bundle"))
+}
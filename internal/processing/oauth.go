@@ -0,0 +1,300 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis/istio/common/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	oathkeeperSvc                = "ory-oathkeeper-proxy.kyma-system.svc.cluster.local"
+	oathkeeperSvcPort     uint32 = 4455
+	oauthAuthenticator           = "oauth2_introspection"
+	allowAuthorizer              = "allow"
+	noopMutator                  = "noop"
+	accessRuleMatchURLTpl        = "<http|https>://%s<%s>"
+)
+
+type oauth struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// introspectionConfig is the configuration of the oauth2_introspection authenticator
+type introspectionConfig struct {
+	RequiredScope []string `json:"required_scope,omitempty"`
+}
+
+func (o *oauth) Process(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	oauthConfig, err := accessRuleConfig(api)
+	if err != nil {
+		return err
+	}
+
+	for i, option := range oauthConfig.Paths {
+		if isPublic(option) {
+			continue
+		}
+		err = o.processAccessRule(ctx, api, option, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = processExternalService(ctx, o.Client, o.Recorder, api)
+	if err != nil {
+		return err
+	}
+
+	oldVS, err := o.getVirtualService(ctx, api)
+	if err != nil {
+		return err
+	}
+
+	if oldVS != nil {
+		newVS := o.prepareVirtualService(api, oldVS.DeepCopy(), publicPaths(oauthConfig))
+		return updateGenerated(ctx, o.Client, o.Recorder, api, oldVS, newVS, "VirtualService")
+	}
+	vs := o.generateVirtualService(api, publicPaths(oauthConfig))
+	return createGenerated(ctx, o.Client, o.Recorder, api, vs, "VirtualService")
+}
+
+func (o *oauth) processAccessRule(ctx context.Context, api *gatewayv2alpha1.Gate, option gatewayv2alpha1.Option, index int) error {
+	oldRule, err := o.getAccessRule(ctx, api, index)
+	if err != nil {
+		return err
+	}
+
+	if oldRule != nil {
+		newRule, err := o.prepareAccessRule(api, oldRule.DeepCopy(), option, index)
+		if err != nil {
+			return err
+		}
+		return updateGenerated(ctx, o.Client, o.Recorder, api, oldRule, newRule, "Rule")
+	}
+
+	rule, err := o.generateAccessRule(api, option, index)
+	if err != nil {
+		return err
+	}
+	return createGenerated(ctx, o.Client, o.Recorder, api, rule, "Rule")
+}
+
+func (o *oauth) getAccessRule(ctx context.Context, api *gatewayv2alpha1.Gate, index int) (*rulev1alpha1.Rule, error) {
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: accessRuleName(api, index)}
+	var rule rulev1alpha1.Rule
+
+	err := o.Client.Get(ctx, namespacedName, &rule)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (o *oauth) prepareAccessRule(api *gatewayv2alpha1.Gate, rule *rulev1alpha1.Rule, option gatewayv2alpha1.Option, index int) (*rulev1alpha1.Rule, error) {
+	spec, err := generateAccessRuleSpec(api, option)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	rule.ObjectMeta.Name = accessRuleName(api, index)
+	rule.ObjectMeta.Namespace = api.ObjectMeta.Namespace
+	rule.ObjectMeta.Labels = generateLabels(api, rule.ObjectMeta.Labels)
+	rule.Spec = *spec
+
+	return rule, nil
+}
+
+func (o *oauth) generateAccessRule(api *gatewayv2alpha1.Gate, option gatewayv2alpha1.Option, index int) (*rulev1alpha1.Rule, error) {
+	spec, err := generateAccessRuleSpec(api, option)
+	if err != nil {
+		return nil, err
+	}
+
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            accessRuleName(api, index),
+		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: []k8sMeta.OwnerReference{*generateOwnerRef(api)},
+	}
+
+	rule := &rulev1alpha1.Rule{
+		ObjectMeta: objectMeta,
+		Spec:       *spec,
+	}
+
+	return rule, nil
+}
+
+func generateAccessRuleSpec(api *gatewayv2alpha1.Gate, option gatewayv2alpha1.Option) (*rulev1alpha1.RuleSpec, error) {
+	authenticators, err := generateAuthenticators(api, option)
+	if err != nil {
+		return nil, err
+	}
+
+	upstreamHost, upstreamPort := upstreamFor(api, option.Path)
+
+	spec := &rulev1alpha1.RuleSpec{
+		Upstream: &rulev1alpha1.Upstream{
+			URL: fmt.Sprintf("http://%s:%d", upstreamHost, int(upstreamPort)),
+		},
+		Match: &rulev1alpha1.Match{
+			URL:     fmt.Sprintf(accessRuleMatchURLTpl, *api.Spec.Service.Host, option.Path),
+			Methods: option.Methods,
+		},
+		Authenticators: authenticators,
+		Authorizer: &rulev1alpha1.Handler{
+			Name: allowAuthorizer,
+		},
+		Mutator: &rulev1alpha1.Handler{
+			Name: noopMutator,
+		},
+	}
+
+	return spec, nil
+}
+
+func (o *oauth) getVirtualService(ctx context.Context, api *gatewayv2alpha1.Gate) (*istiov1alpha3.VirtualService, error) {
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: virtualServiceName(api)}
+	var vs istiov1alpha3.VirtualService
+
+	err := o.Client.Get(ctx, namespacedName, &vs)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &vs, nil
+}
+
+func (o *oauth) prepareVirtualService(api *gatewayv2alpha1.Gate, vs *istiov1alpha3.VirtualService, public []gatewayv2alpha1.Option) *istiov1alpha3.VirtualService {
+	vs.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	vs.ObjectMeta.Name = virtualServiceName(api)
+	vs.ObjectMeta.Namespace = api.ObjectMeta.Namespace
+	vs.ObjectMeta.Labels = generateLabels(api, vs.ObjectMeta.Labels)
+	vs.Spec = *generateOauthVirtualServiceSpec(api, public)
+
+	return vs
+}
+
+func (o *oauth) generateVirtualService(api *gatewayv2alpha1.Gate, public []gatewayv2alpha1.Option) *istiov1alpha3.VirtualService {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            virtualServiceName(api),
+		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: []k8sMeta.OwnerReference{*generateOwnerRef(api)},
+	}
+
+	return &istiov1alpha3.VirtualService{
+		ObjectMeta: objectMeta,
+		Spec:       *generateOauthVirtualServiceSpec(api, public),
+	}
+}
+
+// generateOauthVirtualServiceSpec routes the public paths straight to the services handling them, and all the
+// remaining paths through the auth proxy
+func generateOauthVirtualServiceSpec(api *gatewayv2alpha1.Gate, public []gatewayv2alpha1.Option) *istiov1alpha3.VirtualServiceSpec {
+	var httpRoutes []istiov1alpha3.HTTPRoute
+	for _, option := range public {
+		httpRoutes = append(httpRoutes, generatePublicHTTPRoute(api, option))
+	}
+
+	match := &istiov1alpha3.HTTPMatchRequest{
+		URI: &v1alpha1.StringMatch{
+			Regex: "/.*",
+		},
+	}
+	route := &istiov1alpha3.HTTPRouteDestination{
+		Destination: istiov1alpha3.Destination{
+			Host: oathkeeperSvc,
+			Port: istiov1alpha3.PortSelector{
+				Number: oathkeeperSvcPort,
+			},
+		},
+	}
+
+	httpRoute := istiov1alpha3.HTTPRoute{
+		Match:      []istiov1alpha3.HTTPMatchRequest{*match},
+		Route:      []istiov1alpha3.HTTPRouteDestination{*route},
+		Headers:    generateHeaders(api.Spec.Headers, nil),
+		CorsPolicy: generateCorsPolicy(api),
+	}
+	applyTrafficPolicy(&httpRoute, api.Spec.Traffic)
+
+	return &istiov1alpha3.VirtualServiceSpec{
+		Hosts:    []string{*api.Spec.Service.Host},
+		Gateways: virtualServiceGateways(api),
+		HTTP:     append(httpRoutes, httpRoute),
+	}
+}
+
+// generatePublicHTTPRoute routes the public path straight to the service handling it, restricted to the allowed
+// methods if the path lists them
+func generatePublicHTTPRoute(api *gatewayv2alpha1.Gate, option gatewayv2alpha1.Option) istiov1alpha3.HTTPRoute {
+	upstreamHost, upstreamPort := upstreamFor(api, option.Path)
+
+	var matches []istiov1alpha3.HTTPMatchRequest
+	for _, method := range option.Methods {
+		matches = append(matches, istiov1alpha3.HTTPMatchRequest{
+			URI:    &v1alpha1.StringMatch{Regex: option.Path},
+			Method: &v1alpha1.StringMatch{Exact: method},
+		})
+	}
+	if len(matches) == 0 {
+		matches = []istiov1alpha3.HTTPMatchRequest{{URI: &v1alpha1.StringMatch{Regex: option.Path}}}
+	}
+
+	httpRoute := istiov1alpha3.HTTPRoute{
+		Match: matches,
+		Route: []istiov1alpha3.HTTPRouteDestination{
+			{
+				Destination: istiov1alpha3.Destination{
+					Host: upstreamHost,
+					Port: istiov1alpha3.PortSelector{
+						Number: uint32(upstreamPort),
+					},
+				},
+			},
+		},
+		Headers:    generateHeaders(api.Spec.Headers, nil),
+		CorsPolicy: generateCorsPolicy(api),
+	}
+	applyTrafficPolicy(&httpRoute, api.Spec.Traffic)
+
+	return httpRoute
+}
+
+// isPublic tells whether the path bypasses the auth proxy
+func isPublic(option gatewayv2alpha1.Option) bool {
+	return option.Strategy == gatewayv2alpha1.PASSTHROUGH
+}
+
+// publicPaths returns the paths of the config which bypass the auth proxy
+func publicPaths(config *gatewayv2alpha1.OauthModeConfig) []gatewayv2alpha1.Option {
+	var public []gatewayv2alpha1.Option
+	for _, option := range config.Paths {
+		if isPublic(option) {
+			public = append(public, option)
+		}
+	}
+	return public
+}
+
+func accessRuleName(api *gatewayv2alpha1.Gate, index int) string {
+	return fmt.Sprintf("%s-%s-%d", api.ObjectMeta.Name, *api.Spec.Service.Name, index)
+}
@@ -0,0 +1,124 @@
+package processing
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerateAccessRule(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	option := gatewayv2alpha1.Option{
+		Path:    "/foo",
+		Scopes:  []string{"read", "write"},
+		Methods: []string{"GET", "POST"},
+	}
+
+	strategyOauth := &oauth{}
+	rule, err := strategyOauth.generateAccessRule(exampleAPI, option, 1)
+	assert.NoError(err)
+
+	assert.Equal(rule.ObjectMeta.Name, apiName+"-"+serviceName+"-1")
+	assert.Equal(rule.ObjectMeta.Namespace, apiNamespace)
+
+	assert.Equal(rule.Spec.Upstream.URL, "http://"+serviceName+"."+apiNamespace+".svc.cluster.local:8080")
+	assert.Equal(rule.Spec.Match.URL, "<http|https>://"+serviceHost+"</foo>")
+	assert.Equal(rule.Spec.Match.Methods, []string{"GET", "POST"})
+
+	assert.Equal(len(rule.Spec.Authenticators), 1)
+	assert.Equal(rule.Spec.Authenticators[0].Name, "oauth2_introspection")
+	assert.Equal(string(rule.Spec.Authenticators[0].Config.Raw), `{"required_scope":["read","write"]}`)
+	assert.Equal(rule.Spec.Authorizer.Name, "allow")
+	assert.Equal(rule.Spec.Mutator.Name, "noop")
+
+	assert.Equal(rule.ObjectMeta.OwnerReferences[0].APIVersion, apiAPIVersion)
+	assert.Equal(rule.ObjectMeta.OwnerReferences[0].Kind, apiKind)
+	assert.Equal(rule.ObjectMeta.OwnerReferences[0].Name, apiName)
+	assert.Equal(rule.ObjectMeta.OwnerReferences[0].UID, apiUID)
+}
+
+func TestGenerateOauthVirtualService(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+
+	strategyOauth := &oauth{}
+	vs := strategyOauth.generateVirtualService(exampleAPI, nil)
+
+	assert.Equal(len(vs.Spec.Gateways), 1)
+	assert.Equal(vs.Spec.Gateways[0], apiGateway)
+
+	assert.Equal(len(vs.Spec.Hosts), 1)
+	assert.Equal(vs.Spec.Hosts[0], serviceHost)
+
+	assert.Equal(len(vs.Spec.HTTP), 1)
+	assert.Equal(len(vs.Spec.HTTP[0].Route), 1)
+	assert.Equal(vs.Spec.HTTP[0].Route[0].Destination.Host, "ory-oathkeeper-proxy.kyma-system.svc.cluster.local")
+	assert.Equal(vs.Spec.HTTP[0].Route[0].Destination.Port.Number, uint32(4455))
+	assert.Equal(vs.Spec.HTTP[0].Match[0].URI.Regex, "/.*")
+
+	assert.Equal(vs.ObjectMeta.Name, apiName+"-"+serviceName)
+	assert.Equal(vs.ObjectMeta.Namespace, apiNamespace)
+	assert.Equal(vs.ObjectMeta.OwnerReferences[0].UID, apiUID)
+}
+
+func getOauthAPI() *gatewayv2alpha1.Gate {
+	strategy := gatewayv2alpha1.OAUTH
+	return &gatewayv2alpha1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      apiName,
+			UID:       apiUID,
+			Namespace: apiNamespace,
+		},
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiAPIVersion,
+			Kind:       apiKind,
+		},
+		Spec: gatewayv2alpha1.GateSpec{
+			Gateway: &apiGateway,
+			Service: &gatewayv2alpha1.Service{
+				Name: &serviceName,
+				Host: &serviceHost,
+				Port: &servicePort,
+			},
+			Auth: &gatewayv2alpha1.AuthStrategy{Name: &strategy},
+		},
+	}
+}
+
+func TestGenerateOauthVirtualServiceWithPublicPaths(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	config := &gatewayv2alpha1.OauthModeConfig{
+		Paths: []gatewayv2alpha1.Option{
+			{Path: "/healthz", Strategy: gatewayv2alpha1.PASSTHROUGH, Methods: []string{"GET", "HEAD"}},
+			{Path: "/orders/.*", Strategy: gatewayv2alpha1.OAUTH, Scopes: []string{"read"}},
+			{Path: "/docs", Strategy: gatewayv2alpha1.PASSTHROUGH},
+		},
+	}
+
+	strategyOauth := &oauth{}
+	vs := strategyOauth.generateVirtualService(exampleAPI, publicPaths(config))
+
+	assert.Equal(len(vs.Spec.HTTP), 3)
+
+	assert.Equal(len(vs.Spec.HTTP[0].Match), 2)
+	assert.Equal(vs.Spec.HTTP[0].Match[0].URI.Regex, "/healthz")
+	assert.Equal(vs.Spec.HTTP[0].Match[0].Method.Exact, "GET")
+	assert.Equal(vs.Spec.HTTP[0].Match[1].URI.Regex, "/healthz")
+	assert.Equal(vs.Spec.HTTP[0].Match[1].Method.Exact, "HEAD")
+	assert.Equal(vs.Spec.HTTP[0].Route[0].Destination.Host, serviceName+"."+apiNamespace+".svc.cluster.local")
+	assert.Equal(vs.Spec.HTTP[0].Route[0].Destination.Port.Number, uint32(servicePort))
+
+	assert.Equal(len(vs.Spec.HTTP[1].Match), 1)
+	assert.Equal(vs.Spec.HTTP[1].Match[0].URI.Regex, "/docs")
+	assert.Nil(vs.Spec.HTTP[1].Match[0].Method)
+
+	assert.Equal(vs.Spec.HTTP[2].Match[0].URI.Regex, "/.*")
+	assert.Equal(vs.Spec.HTTP[2].Route[0].Destination.Host, oathkeeperSvc)
+}
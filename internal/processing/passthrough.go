@@ -2,21 +2,34 @@ package processing
 
 import (
 	"context"
-	"fmt"
+
 	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"knative.dev/pkg/apis/istio/common/v1alpha1"
-	networkingv1alpha3 "knative.dev/pkg/apis/istio/v1alpha3"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type passthrough struct {
 	client.Client
+	Recorder record.EventRecorder
+	// Client certificates allowed on the routes, set by the MTLS strategy
+	clientCertificate *gatewayv2alpha1.MTLSModeConfig
+	// Token marking the requests with a verified key, required on the routes by the APIKEY strategy
+	keyVerifiedToken string
 }
 
 func (p *passthrough) Process(ctx context.Context, api *gatewayv2alpha1.Gate) error {
-	fmt.Println("Processing API")
+	err := processExternalService(ctx, p.Client, p.Recorder, api)
+	if err != nil {
+		return err
+	}
+
+	err = processBackends(ctx, p.Client, p.Recorder, api)
+	if err != nil {
+		return err
+	}
 
 	oldVS, err := p.getVirtualService(ctx, api)
 	if err != nil {
@@ -24,18 +37,16 @@ func (p *passthrough) Process(ctx context.Context, api *gatewayv2alpha1.Gate) er
 	}
 
 	if oldVS != nil {
-		newVS := p.prepareVirtualService(api, oldVS)
-		return p.updateVirtualService(ctx, newVS)
-	} else {
-		vs := p.generateVirtualService(api)
-		return p.createVirtualService(ctx, vs)
+		newVS := p.prepareVirtualService(api, oldVS.DeepCopy())
+		return updateGenerated(ctx, p.Client, p.Recorder, api, oldVS, newVS, "VirtualService")
 	}
+	vs := p.generateVirtualService(api)
+	return createGenerated(ctx, p.Client, p.Recorder, api, vs, "VirtualService")
 }
 
-func (p *passthrough) getVirtualService(ctx context.Context, api *gatewayv2alpha1.Gate) (*networkingv1alpha3.VirtualService, error) {
-	virtualServiceName := fmt.Sprintf("%s-%s", api.ObjectMeta.Name, *api.Spec.Service.Name)
-	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: virtualServiceName}
-	var vs networkingv1alpha3.VirtualService
+func (p *passthrough) getVirtualService(ctx context.Context, api *gatewayv2alpha1.Gate) (*istiov1alpha3.VirtualService, error) {
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: virtualServiceName(api)}
+	var vs istiov1alpha3.VirtualService
 
 	err := p.Client.Get(ctx, namespacedName, &vs)
 	if err != nil {
@@ -48,108 +59,72 @@ func (p *passthrough) getVirtualService(ctx context.Context, api *gatewayv2alpha
 	return &vs, nil
 }
 
-func (p *passthrough) createVirtualService(ctx context.Context, vs *networkingv1alpha3.VirtualService) error {
-	return p.Client.Create(ctx, vs)
-}
-
-func (p *passthrough) prepareVirtualService(api *gatewayv2alpha1.Gate, vs *networkingv1alpha3.VirtualService) *networkingv1alpha3.VirtualService {
-	virtualServiceName := fmt.Sprintf("%s-%s", api.ObjectMeta.Name, *api.Spec.Service.Name)
-	controller := true
-
-	ownerRef := &k8sMeta.OwnerReference{
-		Name:       api.ObjectMeta.Name,
-		APIVersion: api.TypeMeta.APIVersion,
-		Kind:       api.TypeMeta.Kind,
-		UID:        api.ObjectMeta.UID,
-		Controller: &controller,
-	}
+func (p *passthrough) prepareVirtualService(api *gatewayv2alpha1.Gate, vs *istiov1alpha3.VirtualService) *istiov1alpha3.VirtualService {
+	ownerRef := generateOwnerRef(api)
 
 	vs.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*ownerRef}
-	vs.ObjectMeta.Name = virtualServiceName
+	vs.ObjectMeta.Name = virtualServiceName(api)
 	vs.ObjectMeta.Namespace = api.ObjectMeta.Namespace
+	vs.ObjectMeta.Labels = generateLabels(api, vs.ObjectMeta.Labels)
 
-	match := &networkingv1alpha3.HTTPMatchRequest{
-		URI: &v1alpha1.StringMatch{
-			Regex: "/.*",
-		},
-	}
-	route := &networkingv1alpha3.HTTPRouteDestination{
-		Destination: networkingv1alpha3.Destination{
-			Host: fmt.Sprintf("%s.%s.svc.cluster.local", *api.Spec.Service.Name, api.ObjectMeta.Namespace),
-			Port: networkingv1alpha3.PortSelector{
-				Number: uint32(*api.Spec.Service.Port),
-			},
-		},
-	}
-
-	spec := &networkingv1alpha3.VirtualServiceSpec{
-		Hosts:    []string{*api.Spec.Service.Host},
-		Gateways: []string{*api.Spec.Gateway},
-		HTTP: []networkingv1alpha3.HTTPRoute{
-			{
-				Match: []networkingv1alpha3.HTTPMatchRequest{*match},
-				Route: []networkingv1alpha3.HTTPRouteDestination{*route},
-			},
-		},
-	}
-
-	vs.Spec = *spec
+	vs.Spec = *p.generateVirtualServiceSpec(api)
 
 	return vs
 
 }
 
-func (p *passthrough) updateVirtualService(ctx context.Context, vs *networkingv1alpha3.VirtualService) error {
-	return p.Client.Update(ctx, vs)
-}
-
-func (p *passthrough) generateVirtualService(api *gatewayv2alpha1.Gate) *networkingv1alpha3.VirtualService {
-	virtualServiceName := fmt.Sprintf("%s-%s", api.ObjectMeta.Name, *api.Spec.Service.Name)
-	controller := true
-
-	ownerRef := &k8sMeta.OwnerReference{
-		Name:       api.ObjectMeta.Name,
-		APIVersion: api.TypeMeta.APIVersion,
-		Kind:       api.TypeMeta.Kind,
-		UID:        api.ObjectMeta.UID,
-		Controller: &controller,
-	}
+func (p *passthrough) generateVirtualService(api *gatewayv2alpha1.Gate) *istiov1alpha3.VirtualService {
+	ownerRef := generateOwnerRef(api)
 
 	objectMeta := k8sMeta.ObjectMeta{
-		Name:            virtualServiceName,
+		Name:            virtualServiceName(api),
 		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
 		OwnerReferences: []k8sMeta.OwnerReference{*ownerRef},
 	}
 
-	match := &networkingv1alpha3.HTTPMatchRequest{
-		URI: &v1alpha1.StringMatch{
-			Regex: "/.*",
-		},
+	vs := &istiov1alpha3.VirtualService{
+		ObjectMeta: objectMeta,
+		Spec:       *p.generateVirtualServiceSpec(api),
 	}
-	route := &networkingv1alpha3.HTTPRouteDestination{
-		Destination: networkingv1alpha3.Destination{
-			Host: fmt.Sprintf("%s.%s.svc.cluster.local", *api.Spec.Service.Name, api.ObjectMeta.Namespace),
-			Port: networkingv1alpha3.PortSelector{
-				Number: uint32(*api.Spec.Service.Port),
-			},
-		},
+
+	return vs
+}
+
+func (p *passthrough) generateVirtualServiceSpec(api *gatewayv2alpha1.Gate) *istiov1alpha3.VirtualServiceSpec {
+	spec := generatePassthroughVirtualServiceSpec(api)
+	if p.clientCertificate != nil {
+		applyClientCertificatePolicy(spec, p.clientCertificate)
+	}
+	if p.keyVerifiedToken != "" {
+		applyKeyVerificationPolicy(spec, p.keyVerifiedToken)
 	}
+	return spec
+}
 
-	spec := &networkingv1alpha3.VirtualServiceSpec{
+// generatePassthroughVirtualServiceSpec routes the requests straight to the services of the Gate. Encrypted
+// connections passed through the gateway are routed by their SNI host.
+func generatePassthroughVirtualServiceSpec(api *gatewayv2alpha1.Gate) *istiov1alpha3.VirtualServiceSpec {
+	spec := &istiov1alpha3.VirtualServiceSpec{
 		Hosts:    []string{*api.Spec.Service.Host},
-		Gateways: []string{*api.Spec.Gateway},
-		HTTP: []networkingv1alpha3.HTTPRoute{
-			{
-				Match: []networkingv1alpha3.HTTPMatchRequest{*match},
-				Route: []networkingv1alpha3.HTTPRouteDestination{*route},
-			},
-		},
+		Gateways: virtualServiceGateways(api),
 	}
 
-	vs := &networkingv1alpha3.VirtualService{
-		ObjectMeta: objectMeta,
-		Spec:       *spec,
+	if api.Spec.TLS != nil && api.Spec.TLS.Mode == gatewayv2alpha1.TLSModePassthrough {
+		spec.TLS = []istiov1alpha3.TLSRoute{
+			{
+				Match: []istiov1alpha3.TLSMatchAttributes{
+					{
+						SniHosts: []string{*api.Spec.Service.Host},
+						Port:     httpsPort,
+					},
+				},
+				Route: generateDefaultDestinations(api),
+			},
+		}
+		return spec
 	}
 
-	return vs
+	spec.HTTP = generateHTTPRoutes(api)
+	return spec
 }
@@ -3,25 +3,39 @@ package processing
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/go-logr/logr"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
 )
 
+const (
+	gateNameLabel       = "gateway.kyma-project.io/gate-name"
+	gateNamespaceLabel  = "gateway.kyma-project.io/gate-namespace"
+	gateGenerationLabel = "gateway.kyma-project.io/gate-generation"
+)
+
 type factory struct {
-	Client client.Client
-	Log    logr.Logger
+	Client   client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
 }
 
 type ProcessingStrategy interface {
 	Process(ctx context.Context, api *gatewayv2alpha1.Gate) error
 }
 
-func NewFactory(client client.Client, logger logr.Logger) *factory {
+func NewFactory(client client.Client, logger logr.Logger, recorder record.EventRecorder) *factory {
 	return &factory{
-		Client: client,
-		Log:    logger,
+		Client:   client,
+		Log:      logger,
+		Recorder: recorder,
 	}
 }
 
@@ -29,8 +43,102 @@ func (f *factory) StrategyFor(strategyName string) (ProcessingStrategy, error) {
 	switch strategyName {
 	case gatewayv2alpha1.PASSTHROUGH:
 		f.Log.Info("PASSTHROUGH processing mode detected")
-		return &passthrough{Client: f.Client}, nil
+		return &passthrough{Client: f.Client, Recorder: f.Recorder}, nil
+	case gatewayv2alpha1.OAUTH:
+		f.Log.Info("OAUTH processing mode detected")
+		return &oauth{Client: f.Client, Recorder: f.Recorder}, nil
+	case gatewayv2alpha1.JWT:
+		f.Log.Info("JWT processing mode detected")
+		return &jwt{Client: f.Client, Recorder: f.Recorder}, nil
+	case gatewayv2alpha1.MTLS:
+		f.Log.Info("MTLS processing mode detected")
+		return &mtls{Client: f.Client, Recorder: f.Recorder}, nil
+	case gatewayv2alpha1.APIKEY:
+		f.Log.Info("APIKEY processing mode detected")
+		return &apiKey{Client: f.Client, Recorder: f.Recorder}, nil
 	default:
 		return nil, fmt.Errorf("Unsupported mode: %s", strategyName)
 	}
 }
+
+func generateOwnerRef(api *gatewayv2alpha1.Gate) *k8sMeta.OwnerReference {
+	controller := true
+
+	return &k8sMeta.OwnerReference{
+		Name:       api.ObjectMeta.Name,
+		APIVersion: api.TypeMeta.APIVersion,
+		Kind:       api.TypeMeta.Kind,
+		UID:        api.ObjectMeta.UID,
+		Controller: &controller,
+	}
+}
+
+// gatewayNamespace returns the namespace of the gateway of the Gate, given as <name>.<namespace>.svc.cluster.local
+func gatewayNamespace(api *gatewayv2alpha1.Gate) string {
+	parts := strings.Split(*api.Spec.Gateway, ".")
+	if len(parts) < 2 {
+		return api.ObjectMeta.Namespace
+	}
+	return parts[1]
+}
+
+func gatewayName(api *gatewayv2alpha1.Gate) string {
+	return strings.Split(*api.Spec.Gateway, ".")[0]
+}
+
+// ownerRefsIn returns the owner references of a resource generated in the given namespace, which cannot point to
+// a Gate in another namespace. Such resources are removed by the finalizer of the Gate.
+func ownerRefsIn(api *gatewayv2alpha1.Gate, namespace string) []k8sMeta.OwnerReference {
+	if namespace != api.ObjectMeta.Namespace {
+		return nil
+	}
+	return []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+}
+
+func virtualServiceName(api *gatewayv2alpha1.Gate) string {
+	return fmt.Sprintf("%s-%s", api.ObjectMeta.Name, *api.Spec.Service.Name)
+}
+
+// NotGeneratedError reports a resource having the name of a resource generated for the Gate, which was not generated
+// for the Gate. The resource is left intact, as it may belong to another Gate or to the platform.
+type NotGeneratedError struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (e *NotGeneratedError) Error() string {
+	return fmt.Sprintf("%s %s/%s already exists and was not generated for the Gate", e.Kind, e.Namespace, e.Name)
+}
+
+// checkGenerated verifies that the existing resource was generated for the Gate before it is updated
+func checkGenerated(api *gatewayv2alpha1.Gate, obj k8sMeta.Object, kind string) error {
+	labels := obj.GetLabels()
+	if labels[gateNameLabel] == api.ObjectMeta.Name && labels[gateNamespaceLabel] == api.ObjectMeta.Namespace {
+		return nil
+	}
+	return &NotGeneratedError{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
+
+// GeneratingGate returns the Gate the resource was generated for, named by its labels, as the resources generated in
+// other namespaces than the one of their Gate cannot point to it with an owner reference
+func GeneratingGate(obj k8sMeta.Object) (types.NamespacedName, bool) {
+	labels := obj.GetLabels()
+	name, namespace := labels[gateNameLabel], labels[gateNamespaceLabel]
+	if name == "" || namespace == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+// generateLabels adds the labels identifying the generating Gate to the given labels
+func generateLabels(api *gatewayv2alpha1.Gate, labels map[string]string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[gateNameLabel] = api.ObjectMeta.Name
+	labels[gateNamespaceLabel] = api.ObjectMeta.Namespace
+	labels[gateGenerationLabel] = strconv.FormatInt(api.ObjectMeta.Generation, 10)
+
+	return labels
+}
@@ -0,0 +1,223 @@
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	localRateLimitFilter = "envoy.filters.http.local_ratelimit"
+	localRateLimitType   = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+	typedStructType      = "type.googleapis.com/udpa.type.v1.TypedStruct"
+	unlimitedTokens      = 4294967295
+)
+
+// gatewayPorts are the ports of the gateway the host of a Gate may be served on. Virtual hosts are named host:port,
+// so the rate limit is configured on both, the patch of a virtual host which does not exist is ignored.
+var gatewayPorts = []int{80, 443}
+
+var fillIntervals = map[gatewayv2alpha1.RateLimitUnit]string{
+	gatewayv2alpha1.RateLimitUnitSecond: "1s",
+	gatewayv2alpha1.RateLimitUnitMinute: "60s",
+	gatewayv2alpha1.RateLimitUnitHour:   "3600s",
+}
+
+type rateLimiter struct {
+	Client   client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+func NewRateLimiter(client client.Client, logger logr.Logger, recorder record.EventRecorder) *rateLimiter {
+	return &rateLimiter{
+		Client:   client,
+		Log:      logger,
+		Recorder: recorder,
+	}
+}
+
+// Process configures Envoy's local rate limit on the virtual hosts of the Gate with an EnvoyFilter in the namespace
+// of its gateway. The local rate limit filter itself is installed on the gateway once, see config/istio.
+// The EnvoyFilter of a Gate without a rate limit is removed by the cleaner as outdated.
+func (r *rateLimiter) Process(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	if api.Spec.RateLimit == nil {
+		return nil
+	}
+
+	spec, err := generateEnvoyFilterSpec(api)
+	if err != nil {
+		return err
+	}
+
+	return processGatewayEnvoyFilter(ctx, r.Client, r.Recorder, api, rateLimitName(api), spec)
+}
+
+// processGatewayEnvoyFilter creates or updates the EnvoyFilter with the given name in the namespace of the gateway
+func processGatewayEnvoyFilter(ctx context.Context, c client.Client, recorder record.EventRecorder, api *gatewayv2alpha1.Gate, name string, spec *istiov1alpha3.EnvoyFilterSpec) error {
+	var envoyFilter istiov1alpha3.EnvoyFilter
+	namespacedName := client.ObjectKey{Namespace: gatewayNamespace(api), Name: name}
+
+	err := c.Get(ctx, namespacedName, &envoyFilter)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return createGenerated(ctx, c, recorder, api, generateEnvoyFilter(api, name, spec), "EnvoyFilter")
+		}
+		return err
+	}
+
+	desired := envoyFilter.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = ownerRefsIn(api, gatewayNamespace(api))
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *spec
+
+	return updateGenerated(ctx, c, recorder, api, &envoyFilter, desired, "EnvoyFilter")
+}
+
+func generateEnvoyFilter(api *gatewayv2alpha1.Gate, name string, spec *istiov1alpha3.EnvoyFilterSpec) *istiov1alpha3.EnvoyFilter {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            name,
+		Namespace:       gatewayNamespace(api),
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: ownerRefsIn(api, gatewayNamespace(api)),
+	}
+
+	return &istiov1alpha3.EnvoyFilter{
+		ObjectMeta: objectMeta,
+		Spec:       *spec,
+	}
+}
+
+func generateEnvoyFilterSpec(api *gatewayv2alpha1.Gate) (*istiov1alpha3.EnvoyFilterSpec, error) {
+	value, err := json.Marshal(generateVirtualHostRateLimit(api.Spec.RateLimit))
+	if err != nil {
+		return nil, err
+	}
+	return generateVirtualHostPatches(api, value), nil
+}
+
+// generateVirtualHostPatches merges the given configuration into the virtual hosts of the Gate
+func generateVirtualHostPatches(api *gatewayv2alpha1.Gate, value []byte) *istiov1alpha3.EnvoyFilterSpec {
+	var patches []istiov1alpha3.EnvoyConfigObjectPatch
+	for _, port := range gatewayPorts {
+		patches = append(patches, istiov1alpha3.EnvoyConfigObjectPatch{
+			ApplyTo: istiov1alpha3.ApplyToVirtualHost,
+			Match: &istiov1alpha3.EnvoyConfigObjectMatch{
+				Context: istiov1alpha3.PatchContextGateway,
+				RouteConfiguration: &istiov1alpha3.RouteConfigurationMatch{
+					Vhost: &istiov1alpha3.VirtualHostMatch{
+						Name: fmt.Sprintf("%s:%d", *api.Spec.Service.Host, port),
+					},
+				},
+			},
+			Patch: &istiov1alpha3.Patch{
+				Operation: istiov1alpha3.PatchOperationMerge,
+				Value:     &runtime.RawExtension{Raw: value},
+			},
+		})
+	}
+
+	return &istiov1alpha3.EnvoyFilterSpec{ConfigPatches: patches}
+}
+
+// generateVirtualHostRateLimit builds the virtual host configuration producing a descriptor per limit. The local rate
+// limit applies the token bucket of the first descriptor matching the request, shared by all the clients.
+func generateVirtualHostRateLimit(rateLimit *gatewayv2alpha1.RateLimit) map[string]interface{} {
+	var rateLimits, descriptors []interface{}
+	for i, limit := range rateLimit.Limits {
+		limitValue := fmt.Sprintf("limit-%d", i)
+		limitAction, limitKey := generateLimitAction(limit, limitValue)
+
+		rateLimits = append(rateLimits, map[string]interface{}{
+			"actions": []interface{}{limitAction},
+		})
+		descriptors = append(descriptors, map[string]interface{}{
+			"entries": []interface{}{
+				map[string]interface{}{"key": limitKey, "value": limitValue},
+			},
+			"token_bucket": generateTokenBucket(limit.Requests, fillIntervals[limit.Unit]),
+		})
+	}
+
+	return map[string]interface{}{
+		"rate_limits": rateLimits,
+		"typed_per_filter_config": map[string]interface{}{
+			localRateLimitFilter: map[string]interface{}{
+				"@type":    typedStructType,
+				"type_url": localRateLimitType,
+				"value": map[string]interface{}{
+					"stat_prefix":     "http_local_rate_limiter",
+					"token_bucket":    generateTokenBucket(unlimitedTokens, "1s"),
+					"filter_enabled":  generateFullFraction("local_rate_limit_enabled"),
+					"filter_enforced": generateFullFraction("local_rate_limit_enforced"),
+					"descriptors":     descriptors,
+				},
+			},
+		},
+	}
+}
+
+// generateLimitAction returns the rate limit action matching the path and methods of the limit, and the key of the
+// descriptor entry it produces
+func generateLimitAction(limit gatewayv2alpha1.RateLimitRule, value string) (map[string]interface{}, string) {
+	var headers []interface{}
+	if limit.Path != nil {
+		headers = append(headers, generateHeaderMatcher(":path", *limit.Path))
+	}
+	if len(limit.Methods) > 0 {
+		headers = append(headers, generateHeaderMatcher(":method", gatewayv2alpha1.StringMatch{Regex: strings.Join(limit.Methods, "|")}))
+	}
+
+	if len(headers) == 0 {
+		return map[string]interface{}{
+			"generic_key": map[string]interface{}{"descriptor_value": value},
+		}, "generic_key"
+	}
+	return map[string]interface{}{
+		"header_value_match": map[string]interface{}{"descriptor_value": value, "headers": headers},
+	}, "header_match"
+}
+
+func generateHeaderMatcher(name string, match gatewayv2alpha1.StringMatch) map[string]interface{} {
+	matcher := map[string]interface{}{"name": name}
+	switch {
+	case match.Exact != "":
+		matcher["exact_match"] = match.Exact
+	case match.Prefix != "":
+		matcher["prefix_match"] = match.Prefix
+	case match.Suffix != "":
+		matcher["suffix_match"] = match.Suffix
+	default:
+		matcher["safe_regex_match"] = map[string]interface{}{"google_re2": map[string]interface{}{}, "regex": match.Regex}
+	}
+	return matcher
+}
+
+func generateTokenBucket(tokens uint32, fillInterval string) map[string]interface{} {
+	return map[string]interface{}{
+		"max_tokens":      tokens,
+		"tokens_per_fill": tokens,
+		"fill_interval":   fillInterval,
+	}
+}
+
+func generateFullFraction(runtimeKey string) map[string]interface{} {
+	return map[string]interface{}{
+		"runtime_key":   runtimeKey,
+		"default_value": map[string]interface{}{"numerator": 100, "denominator": "HUNDRED"},
+	}
+}
+
+func rateLimitName(api *gatewayv2alpha1.Gate) string {
+	return fmt.Sprintf("%s-%s-ratelimit", api.ObjectMeta.Namespace, api.ObjectMeta.Name)
+}
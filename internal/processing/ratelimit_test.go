@@ -0,0 +1,292 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGenerateEnvoyFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.RateLimit = &gatewayv2alpha1.RateLimit{
+		Limits: []gatewayv2alpha1.RateLimitRule{
+			{
+				Path:     &gatewayv2alpha1.StringMatch{Prefix: "/api/search"},
+				Methods:  []string{"GET", "POST"},
+				Requests: 10,
+				Unit:     gatewayv2alpha1.RateLimitUnitSecond,
+			},
+			{
+				Requests: 1000,
+				Unit:     gatewayv2alpha1.RateLimitUnitMinute,
+			},
+		},
+	}
+
+	spec, err := generateEnvoyFilterSpec(exampleAPI)
+	assert.Nil(err)
+
+	envoyFilter := generateEnvoyFilter(exampleAPI, rateLimitName(exampleAPI), spec)
+	assert.Equal(envoyFilter.ObjectMeta.Name, apiNamespace+"-"+apiName+"-ratelimit")
+	assert.Equal(envoyFilter.ObjectMeta.Namespace, apiNamespace)
+	assert.Equal(envoyFilter.ObjectMeta.OwnerReferences[0].UID, apiUID)
+
+	assert.Len(envoyFilter.Spec.ConfigPatches, 2)
+	assert.Equal(envoyFilter.Spec.ConfigPatches[0].ApplyTo, istiov1alpha3.ApplyToVirtualHost)
+	assert.Equal(envoyFilter.Spec.ConfigPatches[0].Match.Context, istiov1alpha3.PatchContextGateway)
+	assert.Equal(envoyFilter.Spec.ConfigPatches[0].Match.RouteConfiguration.Vhost.Name, serviceHost+":80")
+	assert.Equal(envoyFilter.Spec.ConfigPatches[1].Match.RouteConfiguration.Vhost.Name, serviceHost+":443")
+	assert.Equal(envoyFilter.Spec.ConfigPatches[0].Patch.Operation, istiov1alpha3.PatchOperationMerge)
+
+	value := decodeVirtualHostRateLimit(t, envoyFilter.Spec.ConfigPatches[0].Patch.Value.Raw)
+
+	assert.Len(value.RateLimits, 2)
+	assert.Len(value.RateLimits[0].Actions, 1)
+	assert.NotNil(value.RateLimits[0].Actions[0].HeaderValueMatch)
+	assert.NotNil(value.RateLimits[1].Actions[0].GenericKey)
+
+	descriptors := value.TypedPerFilterConfig[localRateLimitFilter].Value.Descriptors
+	assert.Len(descriptors, 2)
+	assert.Equal(descriptors[0].Entries, []envoyDescriptorEntry{{Key: "header_match", Value: "limit-0"}})
+	assert.Equal(descriptors[0].TokenBucket.MaxTokens, uint32(10))
+	assert.Equal(descriptors[0].TokenBucket.FillInterval, "1s")
+	assert.Equal(descriptors[1].Entries, []envoyDescriptorEntry{{Key: "generic_key", Value: "limit-1"}})
+	assert.Equal(descriptors[1].TokenBucket.MaxTokens, uint32(1000))
+	assert.Equal(descriptors[1].TokenBucket.FillInterval, "60s")
+}
+
+func TestGenerateEnvoyFilterInGatewayNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := "kyma-gateway.kyma-system.svc.cluster.local"
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Gateway = &gateway
+	exampleAPI.Spec.RateLimit = &gatewayv2alpha1.RateLimit{
+		Limits: []gatewayv2alpha1.RateLimitRule{{Requests: 5, Unit: gatewayv2alpha1.RateLimitUnitHour}},
+	}
+
+	spec, err := generateEnvoyFilterSpec(exampleAPI)
+	assert.Nil(err)
+
+	envoyFilter := generateEnvoyFilter(exampleAPI, rateLimitName(exampleAPI), spec)
+	assert.Equal(envoyFilter.ObjectMeta.Namespace, "kyma-system")
+	assert.Empty(envoyFilter.ObjectMeta.OwnerReferences)
+
+	gate, ok := GeneratingGate(envoyFilter)
+	assert.True(ok)
+	assert.Equal(gate, types.NamespacedName{Namespace: apiNamespace, Name: apiName})
+	_, ok = GeneratingGate(&istiov1alpha3.EnvoyFilter{})
+	assert.False(ok)
+
+	value := decodeVirtualHostRateLimit(t, envoyFilter.Spec.ConfigPatches[0].Patch.Value.Raw)
+	assert.Len(value.RateLimits, 1)
+	assert.NotNil(value.RateLimits[0].Actions[0].GenericKey)
+}
+
+// The types below mirror the parts of the Envoy v3 API the rate limit of a virtual host is made of, see
+// envoy.config.route.v3.RateLimit and envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit.
+// Fields Envoy does not know are rejected when decoding, the constraints of its validation rules by validate.
+
+type envoyVirtualHostRateLimit struct {
+	RateLimits           []envoyRateLimit                  `json:"rate_limits"`
+	TypedPerFilterConfig map[string]envoyLocalRateLimitAny `json:"typed_per_filter_config"`
+}
+
+type envoyRateLimit struct {
+	Stage      *uint32       `json:"stage,omitempty"`
+	DisableKey string        `json:"disable_key,omitempty"`
+	Actions    []envoyAction `json:"actions"`
+}
+
+type envoyAction struct {
+	RemoteAddress    *struct{}                    `json:"remote_address,omitempty"`
+	RequestHeaders   *envoyRequestHeadersAction   `json:"request_headers,omitempty"`
+	GenericKey       *envoyGenericKeyAction       `json:"generic_key,omitempty"`
+	HeaderValueMatch *envoyHeaderValueMatchAction `json:"header_value_match,omitempty"`
+}
+
+type envoyRequestHeadersAction struct {
+	HeaderName    string `json:"header_name"`
+	DescriptorKey string `json:"descriptor_key"`
+	SkipIfAbsent  bool   `json:"skip_if_absent,omitempty"`
+}
+
+type envoyGenericKeyAction struct {
+	DescriptorValue string `json:"descriptor_value"`
+	DescriptorKey   string `json:"descriptor_key,omitempty"`
+}
+
+type envoyHeaderValueMatchAction struct {
+	DescriptorValue string               `json:"descriptor_value"`
+	ExpectMatch     *bool                `json:"expect_match,omitempty"`
+	Headers         []envoyHeaderMatcher `json:"headers"`
+}
+
+type envoyHeaderMatcher struct {
+	Name           string             `json:"name"`
+	ExactMatch     string             `json:"exact_match,omitempty"`
+	PrefixMatch    string             `json:"prefix_match,omitempty"`
+	SuffixMatch    string             `json:"suffix_match,omitempty"`
+	SafeRegexMatch *envoyRegexMatcher `json:"safe_regex_match,omitempty"`
+	InvertMatch    bool               `json:"invert_match,omitempty"`
+}
+
+type envoyRegexMatcher struct {
+	GoogleRe2 *struct{} `json:"google_re2,omitempty"`
+	Regex     string    `json:"regex"`
+}
+
+type envoyLocalRateLimitAny struct {
+	Type    string              `json:"@type"`
+	TypeURL string              `json:"type_url"`
+	Value   envoyLocalRateLimit `json:"value"`
+}
+
+type envoyLocalRateLimit struct {
+	StatPrefix     string                    `json:"stat_prefix"`
+	TokenBucket    *envoyTokenBucket         `json:"token_bucket,omitempty"`
+	FilterEnabled  *envoyRuntimeFraction     `json:"filter_enabled,omitempty"`
+	FilterEnforced *envoyRuntimeFraction     `json:"filter_enforced,omitempty"`
+	Descriptors    []envoyLocalRateLimitDesc `json:"descriptors,omitempty"`
+	Stage          *uint32                   `json:"stage,omitempty"`
+}
+
+type envoyLocalRateLimitDesc struct {
+	Entries     []envoyDescriptorEntry `json:"entries"`
+	TokenBucket *envoyTokenBucket      `json:"token_bucket"`
+}
+
+type envoyDescriptorEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type envoyTokenBucket struct {
+	MaxTokens     uint32 `json:"max_tokens"`
+	TokensPerFill uint32 `json:"tokens_per_fill,omitempty"`
+	FillInterval  string `json:"fill_interval"`
+}
+
+type envoyRuntimeFraction struct {
+	RuntimeKey   string `json:"runtime_key"`
+	DefaultValue struct {
+		Numerator   uint32 `json:"numerator"`
+		Denominator string `json:"denominator"`
+	} `json:"default_value"`
+}
+
+// decodeVirtualHostRateLimit decodes the virtual host patch strictly into the Envoy types and fails the test when
+// the configuration would be rejected by Envoy, or when a descriptor can never match the entries of the actions
+func decodeVirtualHostRateLimit(t *testing.T, raw []byte) envoyVirtualHostRateLimit {
+	var value envoyVirtualHostRateLimit
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&value); err != nil {
+		t.Fatalf("virtual host rate limit does not match the Envoy API: %v", err)
+	}
+
+	produced := map[string]bool{}
+	for i, rateLimit := range value.RateLimits {
+		entries, err := rateLimit.entries()
+		if err != nil {
+			t.Fatalf("rate limit %d is invalid: %v", i, err)
+		}
+		produced[fmt.Sprint(entries)] = true
+	}
+
+	for name, config := range value.TypedPerFilterConfig {
+		if err := config.validate(); err != nil {
+			t.Fatalf("config of filter %s is invalid: %v", name, err)
+		}
+		for i, descriptor := range config.Value.Descriptors {
+			if !produced[fmt.Sprint(descriptor.Entries)] {
+				t.Fatalf("descriptor %d of filter %s matches no rate limit actions: %v", i, name, descriptor.Entries)
+			}
+		}
+	}
+	return value
+}
+
+// entries returns the descriptor entries the actions produce. Actions producing the value from the request cannot
+// be matched by the static descriptors of the local rate limit, they are rejected.
+func (r envoyRateLimit) entries() ([]envoyDescriptorEntry, error) {
+	if len(r.Actions) == 0 {
+		return nil, fmt.Errorf("actions cannot be empty")
+	}
+
+	var entries []envoyDescriptorEntry
+	for _, action := range r.Actions {
+		switch {
+		case action.GenericKey != nil:
+			if action.GenericKey.DescriptorValue == "" {
+				return nil, fmt.Errorf("generic key value cannot be empty")
+			}
+			key := action.GenericKey.DescriptorKey
+			if key == "" {
+				key = "generic_key"
+			}
+			entries = append(entries, envoyDescriptorEntry{Key: key, Value: action.GenericKey.DescriptorValue})
+		case action.HeaderValueMatch != nil:
+			if action.HeaderValueMatch.DescriptorValue == "" || len(action.HeaderValueMatch.Headers) == 0 {
+				return nil, fmt.Errorf("header value match needs a value and headers")
+			}
+			for _, header := range action.HeaderValueMatch.Headers {
+				if header.Name == "" {
+					return nil, fmt.Errorf("header name cannot be empty")
+				}
+			}
+			entries = append(entries, envoyDescriptorEntry{Key: "header_match", Value: action.HeaderValueMatch.DescriptorValue})
+		default:
+			return nil, fmt.Errorf("action %+v produces a value per request", action)
+		}
+	}
+	return entries, nil
+}
+
+func (c envoyLocalRateLimitAny) validate() error {
+	if c.Type != typedStructType || c.TypeURL != localRateLimitType {
+		return fmt.Errorf("unexpected type %s of %s", c.Type, c.TypeURL)
+	}
+	if c.Value.StatPrefix == "" {
+		return fmt.Errorf("stat prefix cannot be empty")
+	}
+	if err := c.Value.TokenBucket.validate(); err != nil {
+		return err
+	}
+	for _, descriptor := range c.Value.Descriptors {
+		if len(descriptor.Entries) == 0 {
+			return fmt.Errorf("descriptor entries cannot be empty")
+		}
+		for _, entry := range descriptor.Entries {
+			if entry.Key == "" || entry.Value == "" {
+				return fmt.Errorf("descriptor entry %+v needs a key and a value", entry)
+			}
+		}
+		if err := descriptor.TokenBucket.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *envoyTokenBucket) validate() error {
+	if b == nil {
+		return fmt.Errorf("token bucket is required")
+	}
+	if b.MaxTokens == 0 {
+		return fmt.Errorf("max tokens must be positive")
+	}
+	if interval, err := time.ParseDuration(b.FillInterval); err != nil || interval <= 0 {
+		return fmt.Errorf("fill interval %q must be a positive duration", b.FillInterval)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package processing
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	"knative.dev/pkg/apis/istio/common/v1alpha1"
+)
+
+// Match kinds ordered from the most to the least specific one
+const (
+	exactMatch = iota
+	prefixMatch
+	suffixMatch
+	regexMatch
+)
+
+// generateHTTPRoutes creates one HTTPRoute per route of the Gate, the most specific match first,
+// followed by the route of the default service matching all the remaining paths
+func generateHTTPRoutes(api *gatewayv2alpha1.Gate) []istiov1alpha3.HTTPRoute {
+	var httpRoutes []istiov1alpha3.HTTPRoute
+
+	for _, route := range sortRoutes(api.Spec.Routes) {
+		httpRoute := istiov1alpha3.HTTPRoute{
+			Match: []istiov1alpha3.HTTPMatchRequest{
+				{
+					URI: &v1alpha1.StringMatch{
+						Exact:  route.Path.Exact,
+						Prefix: route.Path.Prefix,
+						Suffix: route.Path.Suffix,
+						Regex:  route.Path.Regex,
+					},
+					Headers: generateHeaderMatches(route.Conditions),
+				},
+			},
+			Route: []istiov1alpha3.HTTPRouteDestination{
+				{
+					Destination: istiov1alpha3.Destination{
+						Host: routeServiceHost(api, route.Service),
+						Port: istiov1alpha3.PortSelector{
+							Number: uint32(*route.Service.Port),
+						},
+					},
+				},
+			},
+			Rewrite:    generateRewrite(route.Rewrite),
+			Headers:    generateHeaders(api.Spec.Headers, route.Headers),
+			CorsPolicy: generateCorsPolicy(api),
+		}
+		applyTrafficPolicy(&httpRoute, mergeTrafficPolicy(api.Spec.Traffic, route.Traffic))
+		httpRoutes = append(httpRoutes, httpRoute)
+	}
+
+	defaultRoute := istiov1alpha3.HTTPRoute{
+		Match: []istiov1alpha3.HTTPMatchRequest{
+			{
+				URI: &v1alpha1.StringMatch{
+					Regex: "/.*",
+				},
+			},
+		},
+		Route:      generateDefaultDestinations(api),
+		Headers:    generateHeaders(api.Spec.Headers, nil),
+		CorsPolicy: generateCorsPolicy(api),
+	}
+	applyTrafficPolicy(&defaultRoute, api.Spec.Traffic)
+
+	return append(httpRoutes, defaultRoute)
+}
+
+// upstreamFor returns the host and port of the service handling the given path. Routes with conditions are not
+// considered, as they are not supported by the OAUTH strategy.
+func upstreamFor(api *gatewayv2alpha1.Gate, path string) (string, int32) {
+	for _, route := range sortRoutes(api.Spec.Routes) {
+		if route.Conditions == nil && matches(*route.Path, path) {
+			return routeServiceHost(api, route.Service), *route.Service.Port
+		}
+	}
+	return defaultServiceHost(api), *api.Spec.Service.Port
+}
+
+// sortRoutes orders the routes from the most specific match. Longer values of the same kind go first, followed by
+// routes with conditions before the ones without. Routes of equal specificity keep their order.
+func sortRoutes(routes []gatewayv2alpha1.Route) []gatewayv2alpha1.Route {
+	sorted := make([]gatewayv2alpha1.Route, len(routes))
+	copy(sorted, routes)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iKind, iValue := matchKind(*sorted[i].Path)
+		jKind, jValue := matchKind(*sorted[j].Path)
+		if iKind != jKind {
+			return iKind < jKind
+		}
+		if len(iValue) != len(jValue) {
+			return len(iValue) > len(jValue)
+		}
+		return sorted[i].Conditions != nil && sorted[j].Conditions == nil
+	})
+
+	return sorted
+}
+
+func matchKind(match gatewayv2alpha1.StringMatch) (int, string) {
+	switch {
+	case match.Exact != "":
+		return exactMatch, match.Exact
+	case match.Prefix != "":
+		return prefixMatch, match.Prefix
+	case match.Suffix != "":
+		return suffixMatch, match.Suffix
+	default:
+		return regexMatch, match.Regex
+	}
+}
+
+func matches(match gatewayv2alpha1.StringMatch, path string) bool {
+	kind, value := matchKind(match)
+	switch kind {
+	case exactMatch:
+		return path == value
+	case prefixMatch:
+		return strings.HasPrefix(path, value)
+	case suffixMatch:
+		return strings.HasSuffix(path, value)
+	default:
+		matched, err := regexp.MatchString(fmt.Sprintf("^(?:%s)$", value), path)
+		return err == nil && matched
+	}
+}
+
+// defaultServiceHost returns the host of the default service, which is its name if the service is external
+func defaultServiceHost(api *gatewayv2alpha1.Gate) string {
+	if isExternal(api) {
+		return *api.Spec.Service.Name
+	}
+	return clusterLocalHost(*api.Spec.Service.Name, api.ObjectMeta.Namespace)
+}
+
+func routeServiceHost(api *gatewayv2alpha1.Gate, service *gatewayv2alpha1.RouteService) string {
+	namespace := api.ObjectMeta.Namespace
+	if service.Namespace != nil && *service.Namespace != "" {
+		namespace = *service.Namespace
+	}
+	return clusterLocalHost(*service.Name, namespace)
+}
+
+func clusterLocalHost(name, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace)
+}
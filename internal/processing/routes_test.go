@@ -0,0 +1,63 @@
+package processing
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateHTTPRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	ordersName := "orders"
+	var ordersPort int32 = 8081
+	ordersNamespace := "shop"
+	usersName := "users"
+	var usersPort int32 = 8082
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Regex: "/users/[0-9]+"},
+			Service: &gatewayv2alpha1.RouteService{Name: &usersName, Port: &usersPort},
+		},
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: &ordersName, Namespace: &ordersNamespace, Port: &ordersPort},
+		},
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Exact: "/orders/summary"},
+			Service: &gatewayv2alpha1.RouteService{Name: &usersName, Port: &usersPort},
+		},
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders/archive"},
+			Service: &gatewayv2alpha1.RouteService{Name: &ordersName, Port: &ordersPort},
+		},
+	}
+
+	routes := generateHTTPRoutes(exampleAPI)
+
+	assert.Equal(len(routes), 5)
+	assert.Equal(routes[0].Match[0].URI.Exact, "/orders/summary")
+	assert.Equal(routes[1].Match[0].URI.Prefix, "/orders/archive")
+	assert.Equal(routes[1].Route[0].Destination.Host, ordersName+"."+apiNamespace+".svc.cluster.local")
+	assert.Equal(routes[2].Match[0].URI.Prefix, "/orders")
+	assert.Equal(routes[2].Route[0].Destination.Host, ordersName+".shop.svc.cluster.local")
+	assert.Equal(routes[2].Route[0].Destination.Port.Number, uint32(ordersPort))
+	assert.Equal(routes[3].Match[0].URI.Regex, "/users/[0-9]+")
+	assert.Equal(routes[4].Match[0].URI.Regex, "/.*")
+	assert.Equal(routes[4].Route[0].Destination.Host, serviceName+"."+apiNamespace+".svc.cluster.local")
+
+	host, port := upstreamFor(exampleAPI, "/orders/123")
+	assert.Equal(host, ordersName+".shop.svc.cluster.local")
+	assert.Equal(port, ordersPort)
+
+	host, port = upstreamFor(exampleAPI, "/users/42")
+	assert.Equal(host, usersName+"."+apiNamespace+".svc.cluster.local")
+	assert.Equal(port, usersPort)
+
+	host, port = upstreamFor(exampleAPI, "/other")
+	assert.Equal(host, serviceName+"."+apiNamespace+".svc.cluster.local")
+	assert.Equal(port, servicePort)
+}
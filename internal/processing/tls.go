@@ -0,0 +1,298 @@
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	certmanagerv1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/certmanager/v1alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// certificateNameAnnotation is set by cert-manager on the secrets it stores the certificates in
+	certificateNameAnnotation = "certmanager.k8s.io/certificate-name"
+
+	httpsPort   = 443
+	httpPort    = 80
+	caSecretKey = "cacert"
+)
+
+type tlsProcessor struct {
+	Client               client.Client
+	Log                  logr.Logger
+	Recorder             record.EventRecorder
+	CertificateNamespace string
+}
+
+// NewTLSProcessor creates the processor of the TLS configuration of the Gates. The certificates are stored in the
+// given namespace of the gateway workload, where the gateway reads them from.
+func NewTLSProcessor(client client.Client, logger logr.Logger, recorder record.EventRecorder, certificateNamespace string) *tlsProcessor {
+	return &tlsProcessor{
+		Client:               client,
+		Log:                  logger,
+		Recorder:             recorder,
+		CertificateNamespace: certificateNamespace,
+	}
+}
+
+// Process exposes the host of the Gate over TLS with a Gateway generated for the Gate, which selects the workload of
+// the gateway of the Gate. The certificate is requested from cert-manager if the Gate defines its issuer. The CA bundle
+// of the MTLS strategy is copied next to the certificate, where the gateway verifies the client certificates with it.
+// The Gateway and the Certificate of a Gate without TLS are removed by the cleaner as outdated.
+func (t *tlsProcessor) Process(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	if api.Spec.TLS == nil {
+		return nil
+	}
+
+	var gateway istiov1alpha3.Gateway
+	err := t.Client.Get(ctx, client.ObjectKey{Namespace: gatewayNamespace(api), Name: gatewayName(api)}, &gateway)
+	if err != nil {
+		return fmt.Errorf("gateway %s of the Gate cannot be read: %v", *api.Spec.Gateway, err)
+	}
+
+	err = t.processGateway(ctx, api, gateway.Spec.Selector)
+	if err != nil {
+		return err
+	}
+
+	if *api.Spec.Auth.Name == gatewayv2alpha1.MTLS {
+		err = t.processCABundle(ctx, api)
+		if err != nil {
+			return err
+		}
+	}
+
+	if api.Spec.TLS.Issuer == nil {
+		return nil
+	}
+	return t.processCertificate(ctx, api)
+}
+
+func (t *tlsProcessor) processGateway(ctx context.Context, api *gatewayv2alpha1.Gate, selector map[string]string) error {
+	var gateway istiov1alpha3.Gateway
+	namespacedName := client.ObjectKey{Namespace: api.GetNamespace(), Name: virtualServiceName(api)}
+
+	err := t.Client.Get(ctx, namespacedName, &gateway)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return createGenerated(ctx, t.Client, t.Recorder, api, generateGateway(api, selector), "Gateway")
+		}
+		return err
+	}
+
+	desired := gateway.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = []k8sMeta.OwnerReference{*generateOwnerRef(api)}
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *generateGatewaySpec(api, selector)
+
+	return updateGenerated(ctx, t.Client, t.Recorder, api, &gateway, desired, "Gateway")
+}
+
+// processCertificate requests the certificate of the Gate in the shared certificate namespace. A Certificate which
+// was not generated for the Gate is not taken over, nor is a secret which cert-manager did not store the certificate
+// of the same name in.
+func (t *tlsProcessor) processCertificate(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	var certificate certmanagerv1alpha1.Certificate
+	namespacedName := client.ObjectKey{Namespace: t.CertificateNamespace, Name: tlsSecretName(api)}
+
+	err := t.Client.Get(ctx, namespacedName, &certificate)
+	if err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+
+		var secret corev1.Secret
+		err = t.Client.Get(ctx, namespacedName, &secret)
+		if err == nil && secret.Annotations[certificateNameAnnotation] != namespacedName.Name {
+			return &NotGeneratedError{Kind: "Secret", Namespace: secret.Namespace, Name: secret.Name}
+		}
+		if err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return createGenerated(ctx, t.Client, t.Recorder, api, generateCertificate(api, t.CertificateNamespace), "Certificate")
+	}
+	err = checkGenerated(api, &certificate, "Certificate")
+	if err != nil {
+		return err
+	}
+
+	desired := certificate.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = ownerRefsIn(api, t.CertificateNamespace)
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Spec = *generateCertificateSpec(api)
+
+	return updateGenerated(ctx, t.Client, t.Recorder, api, &certificate, desired, "Certificate")
+}
+
+// processCABundle copies the CA bundle of the MTLS strategy next to the credential of the Gate. A secret which was
+// not generated for the Gate is not overwritten.
+func (t *tlsProcessor) processCABundle(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	var mtlsConfig gatewayv2alpha1.MTLSModeConfig
+	err := json.Unmarshal(api.Spec.Auth.Config.Raw, &mtlsConfig)
+	if err != nil {
+		return err
+	}
+
+	var bundle corev1.Secret
+	err = t.Client.Get(ctx, client.ObjectKey{Namespace: api.GetNamespace(), Name: mtlsConfig.CABundle.SecretName}, &bundle)
+	if err != nil {
+		return fmt.Errorf("CA bundle secret %s of the Gate cannot be read: %v", mtlsConfig.CABundle.SecretName, err)
+	}
+	caCert, ok := bundle.Data[caBundleKey(&mtlsConfig)]
+	if !ok {
+		return fmt.Errorf("CA bundle secret %s of the Gate has no %s key", mtlsConfig.CABundle.SecretName, caBundleKey(&mtlsConfig))
+	}
+
+	var secret corev1.Secret
+	namespacedName := client.ObjectKey{Namespace: t.CertificateNamespace, Name: caSecretName(api)}
+
+	err = t.Client.Get(ctx, namespacedName, &secret)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return createGenerated(ctx, t.Client, t.Recorder, api, generateCASecret(api, t.CertificateNamespace, caCert), "Secret")
+		}
+		return err
+	}
+	err = checkGenerated(api, &secret, "Secret")
+	if err != nil {
+		return err
+	}
+
+	desired := secret.DeepCopy()
+	desired.ObjectMeta.OwnerReferences = ownerRefsIn(api, t.CertificateNamespace)
+	desired.ObjectMeta.Labels = generateLabels(api, desired.ObjectMeta.Labels)
+	desired.Data = map[string][]byte{caSecretKey: caCert}
+
+	return updateGenerated(ctx, t.Client, t.Recorder, api, &secret, desired, "Secret")
+}
+
+func generateGateway(api *gatewayv2alpha1.Gate, selector map[string]string) *istiov1alpha3.Gateway {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            virtualServiceName(api),
+		Namespace:       api.ObjectMeta.Namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: []k8sMeta.OwnerReference{*generateOwnerRef(api)},
+	}
+
+	return &istiov1alpha3.Gateway{
+		ObjectMeta: objectMeta,
+		Spec:       *generateGatewaySpec(api, selector),
+	}
+}
+
+// generateGatewaySpec serves the host over TLS. Plain HTTP requests are redirected, unless the encrypted connections
+// are passed through to the service.
+func generateGatewaySpec(api *gatewayv2alpha1.Gate, selector map[string]string) *istiov1alpha3.GatewaySpec {
+	hosts := []string{*api.Spec.Service.Host}
+	mode := api.Spec.TLS.Mode
+
+	if mode == gatewayv2alpha1.TLSModePassthrough {
+		return &istiov1alpha3.GatewaySpec{
+			Selector: selector,
+			Servers: []istiov1alpha3.Server{
+				{
+					Port:  istiov1alpha3.Port{Number: httpsPort, Protocol: istiov1alpha3.PortProtocol("TLS"), Name: "tls"},
+					Hosts: hosts,
+					TLS:   &istiov1alpha3.TLSOptions{Mode: istiov1alpha3.TLSModePassThrough},
+				},
+			},
+		}
+	}
+
+	return &istiov1alpha3.GatewaySpec{
+		Selector: selector,
+		Servers: []istiov1alpha3.Server{
+			{
+				Port:  istiov1alpha3.Port{Number: httpsPort, Protocol: istiov1alpha3.ProtocolHTTPS, Name: "https"},
+				Hosts: hosts,
+				TLS: &istiov1alpha3.TLSOptions{
+					Mode:           istiov1alpha3.TLSMode(mode),
+					CredentialName: tlsSecretName(api),
+				},
+			},
+			{
+				Port:  istiov1alpha3.Port{Number: httpPort, Protocol: istiov1alpha3.ProtocolHTTP, Name: "http"},
+				Hosts: hosts,
+				TLS:   &istiov1alpha3.TLSOptions{HTTPSRedirect: true},
+			},
+		},
+	}
+}
+
+func generateCertificate(api *gatewayv2alpha1.Gate, namespace string) *certmanagerv1alpha1.Certificate {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            tlsSecretName(api),
+		Namespace:       namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: ownerRefsIn(api, namespace),
+	}
+
+	return &certmanagerv1alpha1.Certificate{
+		ObjectMeta: objectMeta,
+		Spec:       *generateCertificateSpec(api),
+	}
+}
+
+func generateCertificateSpec(api *gatewayv2alpha1.Gate) *certmanagerv1alpha1.CertificateSpec {
+	issuer := api.Spec.TLS.Issuer
+	kind := issuer.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+
+	return &certmanagerv1alpha1.CertificateSpec{
+		SecretName: tlsSecretName(api),
+		CommonName: *api.Spec.Service.Host,
+		DNSNames:   []string{*api.Spec.Service.Host},
+		IssuerRef: certmanagerv1alpha1.ObjectReference{
+			Name: issuer.Name,
+			Kind: kind,
+		},
+	}
+}
+
+// generateCASecret stores the CA bundle in the secret the gateway reads the CA certificates of its credential from
+func generateCASecret(api *gatewayv2alpha1.Gate, namespace string, caCert []byte) *corev1.Secret {
+	objectMeta := k8sMeta.ObjectMeta{
+		Name:            caSecretName(api),
+		Namespace:       namespace,
+		Labels:          generateLabels(api, nil),
+		OwnerReferences: ownerRefsIn(api, namespace),
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: objectMeta,
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{caSecretKey: caCert},
+	}
+}
+
+// virtualServiceGateways returns the gateways serving the VirtualService of the Gate
+func virtualServiceGateways(api *gatewayv2alpha1.Gate) []string {
+	if api.Spec.TLS != nil {
+		return []string{virtualServiceName(api)}
+	}
+	return []string{*api.Spec.Gateway}
+}
+
+// tlsSecretName returns the name of the secret holding the certificate. The secret of a certificate requested for the
+// Gate is always named after the Gate, a name referencing an existing secret cannot be set along with an issuer.
+func tlsSecretName(api *gatewayv2alpha1.Gate) string {
+	if api.Spec.TLS.SecretName != "" {
+		return api.Spec.TLS.SecretName
+	}
+	return fmt.Sprintf("%s-%s-tls", api.ObjectMeta.Namespace, api.ObjectMeta.Name)
+}
+
+// caSecretName returns the name of the secret holding the CA certificates of the credential of the Gateway
+func caSecretName(api *gatewayv2alpha1.Gate) string {
+	return tlsSecretName(api) + "-cacert"
+}
@@ -0,0 +1,168 @@
+package processing
+
+import (
+	"context"
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	certmanagerv1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/certmanager/v1alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerateGateway(t *testing.T) {
+	assert := assert.New(t)
+	selector := map[string]string{"istio": "ingressgateway"}
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeSimple}
+
+	gateway := generateGateway(exampleAPI, selector)
+	assert.Equal(gateway.ObjectMeta.Name, apiName+"-"+serviceName)
+	assert.Equal(gateway.ObjectMeta.Namespace, apiNamespace)
+	assert.Equal(gateway.ObjectMeta.OwnerReferences[0].UID, apiUID)
+	assert.Equal(gateway.Spec.Selector, selector)
+
+	assert.Len(gateway.Spec.Servers, 2)
+	assert.Equal(gateway.Spec.Servers[0].Port.Number, 443)
+	assert.Equal(gateway.Spec.Servers[0].Port.Protocol, istiov1alpha3.ProtocolHTTPS)
+	assert.Equal(gateway.Spec.Servers[0].Hosts, []string{serviceHost})
+	assert.Equal(gateway.Spec.Servers[0].TLS.Mode, istiov1alpha3.TLSModeSimple)
+	assert.Equal(gateway.Spec.Servers[0].TLS.CredentialName, apiNamespace+"-"+apiName+"-tls")
+	assert.Equal(gateway.Spec.Servers[1].Port.Number, 80)
+	assert.True(gateway.Spec.Servers[1].TLS.HTTPSRedirect)
+
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeMutual, SecretName: "shop-cert"}
+	spec := generateGatewaySpec(exampleAPI, selector)
+	assert.Equal(spec.Servers[0].TLS.Mode, istiov1alpha3.TLSModeMutual)
+	assert.Equal(spec.Servers[0].TLS.CredentialName, "shop-cert")
+
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModePassthrough}
+	spec = generateGatewaySpec(exampleAPI, selector)
+	assert.Len(spec.Servers, 1)
+	assert.Equal(spec.Servers[0].Port.Protocol, istiov1alpha3.PortProtocol("TLS"))
+	assert.Equal(spec.Servers[0].TLS.Mode, istiov1alpha3.TLSModePassThrough)
+}
+
+func TestGenerateCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{
+		Mode:   gatewayv2alpha1.TLSModeSimple,
+		Issuer: &gatewayv2alpha1.CertificateIssuer{Name: "letsencrypt"},
+	}
+
+	certificate := generateCertificate(exampleAPI, "istio-system")
+	assert.Equal(certificate.ObjectMeta.Name, apiNamespace+"-"+apiName+"-tls")
+	assert.Equal(certificate.ObjectMeta.Namespace, "istio-system")
+	assert.Empty(certificate.ObjectMeta.OwnerReferences)
+	assert.Equal(certificate.ObjectMeta.Labels[gateNameLabel], apiName)
+
+	assert.Equal(certificate.Spec.SecretName, apiNamespace+"-"+apiName+"-tls")
+	assert.Equal(certificate.Spec.CommonName, serviceHost)
+	assert.Equal(certificate.Spec.DNSNames, []string{serviceHost})
+	assert.Equal(certificate.Spec.IssuerRef.Name, "letsencrypt")
+	assert.Equal(certificate.Spec.IssuerRef.Kind, "Issuer")
+}
+
+func TestGenerateVirtualServiceWithTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeSimple}
+
+	spec := generatePassthroughVirtualServiceSpec(exampleAPI)
+	assert.Equal(spec.Gateways, []string{apiName + "-" + serviceName})
+	assert.NotEmpty(spec.HTTP)
+	assert.Empty(spec.TLS)
+
+	exampleAPI.Spec.TLS.Mode = gatewayv2alpha1.TLSModePassthrough
+	spec = generatePassthroughVirtualServiceSpec(exampleAPI)
+	assert.Empty(spec.HTTP)
+	assert.Len(spec.TLS, 1)
+	assert.Equal(spec.TLS[0].Match[0].SniHosts, []string{serviceHost})
+	assert.Equal(spec.TLS[0].Match[0].Port, 443)
+	assert.Equal(spec.TLS[0].Route[0].Destination.Host, serviceName+"."+apiNamespace+".svc.cluster.local")
+}
+
+func TestProcessCertificateNotGenerated(t *testing.T) {
+	assert := assert.New(t)
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{
+		Mode:   gatewayv2alpha1.TLSModeSimple,
+		Issuer: &gatewayv2alpha1.CertificateIssuer{Name: "letsencrypt"},
+	}
+	name := apiNamespace + "-" + apiName + "-tls"
+
+	foreign := &certmanagerv1alpha1.Certificate{
+		ObjectMeta: k8sMeta.ObjectMeta{Name: name, Namespace: "istio-system", Labels: map[string]string{gateNameLabel: apiName, gateNamespaceLabel: "other"}},
+		Spec:       certmanagerv1alpha1.CertificateSpec{SecretName: name, CommonName: "platform.example.com"},
+	}
+	processor := NewTLSProcessor(fake.NewFakeClientWithScheme(tlsScheme(t), foreign), nil, record.NewFakeRecorder(10), "istio-system")
+	err := processor.processCertificate(context.TODO(), exampleAPI)
+	assert.Equal(err, &NotGeneratedError{Kind: "Certificate", Namespace: "istio-system", Name: name})
+
+	var stored certmanagerv1alpha1.Certificate
+	assert.NoError(processor.Client.Get(context.TODO(), client.ObjectKey{Namespace: "istio-system", Name: name}, &stored))
+	assert.Equal(stored.Spec.CommonName, "platform.example.com")
+
+	secret := &corev1.Secret{ObjectMeta: k8sMeta.ObjectMeta{Name: name, Namespace: "istio-system"}}
+	processor = NewTLSProcessor(fake.NewFakeClientWithScheme(tlsScheme(t), secret), nil, record.NewFakeRecorder(10), "istio-system")
+	err = processor.processCertificate(context.TODO(), exampleAPI)
+	assert.Equal(err, &NotGeneratedError{Kind: "Secret", Namespace: "istio-system", Name: name})
+
+	secret.Annotations = map[string]string{certificateNameAnnotation: name}
+	processor = NewTLSProcessor(fake.NewFakeClientWithScheme(tlsScheme(t), secret), nil, record.NewFakeRecorder(10), "istio-system")
+	assert.NoError(processor.processCertificate(context.TODO(), exampleAPI))
+	assert.NoError(processor.processCertificate(context.TODO(), exampleAPI))
+}
+
+func TestProcessCABundleNotGenerated(t *testing.T) {
+	assert := assert.New(t)
+
+	strategy := gatewayv2alpha1.MTLS
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Auth = &gatewayv2alpha1.AuthStrategy{
+		Name:   &strategy,
+		Config: &runtime.RawExtension{Raw: []byte(`{"caBundle":{"secretName":"partner-ca"}}`)},
+	}
+	exampleAPI.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeMutual}
+	name := apiNamespace + "-" + apiName + "-tls-cacert"
+
+	bundle := &corev1.Secret{
+		ObjectMeta: k8sMeta.ObjectMeta{Name: "partner-ca", Namespace: apiNamespace},
+		Data:       map[string][]byte{"ca.crt": []byte("partner CA")},
+	}
+	foreign := &corev1.Secret{
+		ObjectMeta: k8sMeta.ObjectMeta{Name: name, Namespace: "istio-system"},
+		Data:       map[string][]byte{caSecretKey: []byte("platform CA")},
+	}
+	processor := NewTLSProcessor(fake.NewFakeClientWithScheme(tlsScheme(t), bundle, foreign), nil, record.NewFakeRecorder(10), "istio-system")
+	err := processor.processCABundle(context.TODO(), exampleAPI)
+	assert.Equal(err, &NotGeneratedError{Kind: "Secret", Namespace: "istio-system", Name: name})
+
+	var stored corev1.Secret
+	assert.NoError(processor.Client.Get(context.TODO(), client.ObjectKey{Namespace: "istio-system", Name: name}, &stored))
+	assert.Equal(stored.Data[caSecretKey], []byte("platform CA"))
+
+	processor = NewTLSProcessor(fake.NewFakeClientWithScheme(tlsScheme(t), bundle), nil, record.NewFakeRecorder(10), "istio-system")
+	assert.NoError(processor.processCABundle(context.TODO(), exampleAPI))
+	assert.NoError(processor.Client.Get(context.TODO(), client.ObjectKey{Namespace: "istio-system", Name: name}, &stored))
+	assert.Equal(stored.Data[caSecretKey], []byte("partner CA"))
+}
+
+func tlsScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, certmanagerv1alpha1.AddToScheme(scheme))
+	return scheme
+}
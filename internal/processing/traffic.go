@@ -0,0 +1,84 @@
+package processing
+
+import (
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+)
+
+// mergeTrafficPolicy overrides the traffic policy of the Gate with the settings defined by the route
+func mergeTrafficPolicy(gate, route *gatewayv2alpha1.TrafficPolicy) *gatewayv2alpha1.TrafficPolicy {
+	if route == nil {
+		return gate
+	}
+	if gate == nil {
+		return route
+	}
+
+	merged := gate.DeepCopy()
+	if route.Timeout != "" {
+		merged.Timeout = route.Timeout
+	}
+	if route.Retries != nil {
+		merged.Retries = route.Retries
+	}
+	if route.Fault != nil {
+		merged.Fault = route.Fault
+	}
+	return merged
+}
+
+// applyTrafficPolicy sets the timeout, the retries and the fault injection of the HTTP route
+func applyTrafficPolicy(httpRoute *istiov1alpha3.HTTPRoute, traffic *gatewayv2alpha1.TrafficPolicy) {
+	if traffic == nil {
+		return
+	}
+
+	httpRoute.Timeout = traffic.Timeout
+
+	if traffic.Retries != nil {
+		httpRoute.Retries = &istiov1alpha3.HTTPRetry{
+			Attempts:      int(traffic.Retries.Attempts),
+			PerTryTimeout: traffic.Retries.PerTryTimeout,
+			RetryOn:       traffic.Retries.RetryOn,
+		}
+	}
+
+	if traffic.Fault != nil {
+		httpRoute.Fault = &istiov1alpha3.HTTPFaultInjection{}
+		if delay := traffic.Fault.Delay; delay != nil {
+			httpRoute.Fault.Delay = &istiov1alpha3.InjectDelay{
+				Percent:    int(delay.Percent),
+				FixedDelay: delay.FixedDelay,
+			}
+		}
+		if abort := traffic.Fault.Abort; abort != nil {
+			httpRoute.Fault.Abort = &istiov1alpha3.InjectAbort{
+				Percent:    int(abort.Percent),
+				HTTPStatus: int(abort.HTTPStatus),
+			}
+		}
+	}
+}
+
+func removeRequestHeader(httpRoute *istiov1alpha3.HTTPRoute, name string) {
+	if httpRoute.Headers == nil {
+		httpRoute.Headers = &istiov1alpha3.Headers{}
+	}
+	if httpRoute.Headers.Request == nil {
+		httpRoute.Headers.Request = &istiov1alpha3.HeaderOperations{}
+	}
+	httpRoute.Headers.Request.Remove = append(httpRoute.Headers.Request.Remove, name)
+}
+
+func setRequestHeader(httpRoute *istiov1alpha3.HTTPRoute, name, value string) {
+	if httpRoute.Headers == nil {
+		httpRoute.Headers = &istiov1alpha3.Headers{}
+	}
+	if httpRoute.Headers.Request == nil {
+		httpRoute.Headers.Request = &istiov1alpha3.HeaderOperations{}
+	}
+	if httpRoute.Headers.Request.Set == nil {
+		httpRoute.Headers.Request.Set = map[string]string{}
+	}
+	httpRoute.Headers.Request.Set[name] = value
+}
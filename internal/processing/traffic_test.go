@@ -0,0 +1,66 @@
+package processing
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateHTTPRoutesTrafficPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	ordersName := "orders"
+	var ordersPort int32 = 8081
+
+	exampleAPI := getOauthAPI()
+	exampleAPI.Spec.Traffic = &gatewayv2alpha1.TrafficPolicy{
+		Timeout: "10s",
+		Retries: &gatewayv2alpha1.Retries{Attempts: 3, PerTryTimeout: "2s", RetryOn: "5xx,connect-failure"},
+	}
+	exampleAPI.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: &ordersName, Port: &ordersPort},
+			Traffic: &gatewayv2alpha1.TrafficPolicy{
+				Timeout: "30s",
+				Fault: &gatewayv2alpha1.FaultInjection{
+					Delay: &gatewayv2alpha1.FaultDelay{Percent: 10, FixedDelay: "5s"},
+					Abort: &gatewayv2alpha1.FaultAbort{Percent: 5, HTTPStatus: 503},
+				},
+			},
+		},
+	}
+
+	routes := generateHTTPRoutes(exampleAPI)
+	assert.Len(routes, 2)
+
+	orders := routes[0]
+	assert.Equal(orders.Timeout, "30s")
+	assert.Equal(orders.Retries.Attempts, 3)
+	assert.Equal(orders.Retries.PerTryTimeout, "2s")
+	assert.Equal(orders.Retries.RetryOn, "5xx,connect-failure")
+	assert.Equal(orders.Fault.Delay.Percent, 10)
+	assert.Equal(orders.Fault.Delay.FixedDelay, "5s")
+	assert.Equal(orders.Fault.Abort.Percent, 5)
+	assert.Equal(orders.Fault.Abort.HTTPStatus, 503)
+
+	defaultRoute := routes[1]
+	assert.Equal(defaultRoute.Timeout, "10s")
+	assert.Equal(defaultRoute.Retries.Attempts, 3)
+	assert.Nil(defaultRoute.Fault)
+
+	vs := (&oauth{}).generateVirtualService(exampleAPI, nil)
+	assert.Equal(vs.Spec.HTTP[0].Timeout, "10s")
+	assert.Equal(vs.Spec.HTTP[0].Retries.Attempts, 3)
+}
+
+func TestGenerateHTTPRoutesWithoutTrafficPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	routes := generateHTTPRoutes(getOauthAPI())
+	assert.Empty(routes[0].Timeout)
+	assert.Nil(routes[0].Retries)
+	assert.Nil(routes[0].Fault)
+	assert.Nil(routes[0].Headers)
+}
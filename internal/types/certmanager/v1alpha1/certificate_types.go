@@ -0,0 +1,60 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateSpec defines the desired state of Certificate
+type CertificateSpec struct {
+	// SecretName is the name of the secret the certificate and its private key are stored in
+	SecretName string `json:"secretName"`
+	// CommonName is the common name of the certificate
+	CommonName string `json:"commonName,omitempty"`
+	// DNSNames are the subject alternative names of the certificate
+	DNSNames []string `json:"dnsNames,omitempty"`
+	// IssuerRef references the issuer requested to sign the certificate
+	IssuerRef ObjectReference `json:"issuerRef"`
+}
+
+// ObjectReference references an Issuer or a ClusterIssuer
+type ObjectReference struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// Certificate is the Schema for the certificates API
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CertificateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CertificateList contains a list of Certificate
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Certificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Certificate{}, &CertificateList{})
+}
@@ -0,0 +1,101 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AuthorizationPolicyAction string
+
+const (
+	AuthorizationPolicyActionAllow AuthorizationPolicyAction = "ALLOW"
+)
+
+// AuthorizationPolicySpec defines the requests the selected workloads accept. With the ALLOW action, the requests
+// matching none of the rules are rejected.
+type AuthorizationPolicySpec struct {
+	// The workloads the policy applies to, all the workloads of the namespace if not set
+	Selector *WorkloadSelector `json:"selector,omitempty"`
+	// The rules matching the requests
+	Rules []Rule `json:"rules,omitempty"`
+	// The action applied to the matching requests, ALLOW if not set
+	Action AuthorizationPolicyAction `json:"action,omitempty"`
+}
+
+// Rule matches the requests from all of its sources to all of its operations meeting all of its conditions
+type Rule struct {
+	// The sources of the requests, any if not set
+	From []RuleFrom `json:"from,omitempty"`
+	// The operations of the requests, any if not set
+	To []RuleTo `json:"to,omitempty"`
+	// The conditions on the attributes of the requests
+	When []Condition `json:"when,omitempty"`
+}
+
+// Condition matches the requests whose attribute has one of the values
+type Condition struct {
+	// The attribute of the requests, e.g. request.auth.claims[iss] for a claim of the token
+	Key string `json:"key"`
+	// The values of the attribute, one of which is matched. A claim which is a list matches if one of its items does.
+	Values []string `json:"values,omitempty"`
+}
+
+// RuleFrom matches the source of the requests
+type RuleFrom struct {
+	Source *Source `json:"source,omitempty"`
+}
+
+// Source matches the authenticated peers and token principals of the requests
+type Source struct {
+	// The principals of the tokens, as <issuer>/<subject>, "*" matching any valid token
+	RequestPrincipals []string `json:"requestPrincipals,omitempty"`
+}
+
+// RuleTo matches the operation of the requests
+type RuleTo struct {
+	Operation *Operation `json:"operation,omitempty"`
+}
+
+// Operation matches the paths of the requests
+type Operation struct {
+	// The paths of the requests, matched exactly, by prefix with a trailing "*" or by suffix with a leading "*"
+	Paths []string `json:"paths,omitempty"`
+	// The paths the requests do not match, like the paths
+	NotPaths []string `json:"notPaths,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// AuthorizationPolicy is the Schema for the authorizationpolicies API
+type AuthorizationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AuthorizationPolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// AuthorizationPolicyList contains a list of AuthorizationPolicy
+type AuthorizationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthorizationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthorizationPolicy{}, &AuthorizationPolicyList{})
+}
@@ -0,0 +1,67 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadSelector selects the pods a policy applies to
+type WorkloadSelector struct {
+	// The labels of the selected pods
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// RequestAuthenticationSpec defines the tokens accepted by the selected workloads. The requests with an invalid token
+// are rejected, the requests without a token are accepted unless an AuthorizationPolicy requires one.
+type RequestAuthenticationSpec struct {
+	// The workloads the policy applies to, all the workloads of the namespace if not set
+	Selector *WorkloadSelector `json:"selector,omitempty"`
+	// The issuers of the accepted tokens
+	JWTRules []JWTRule `json:"jwtRules,omitempty"`
+}
+
+// JWTRule describes the tokens of an issuer and how to verify them
+type JWTRule struct {
+	// The issuer of the tokens
+	Issuer string `json:"issuer"`
+	// The audiences the tokens must be issued for, any if not set
+	Audiences []string `json:"audiences,omitempty"`
+	// The URL of the public keys verifying the signature of the tokens
+	JWKSURI string `json:"jwksUri,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// RequestAuthentication is the Schema for the requestauthentications API
+type RequestAuthentication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RequestAuthenticationSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// RequestAuthenticationList contains a list of RequestAuthentication
+type RequestAuthenticationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RequestAuthentication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RequestAuthentication{}, &RequestAuthenticationList{})
+}
@@ -0,0 +1,132 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type ApplyTo string
+
+const (
+	ApplyToHTTPFilter  ApplyTo = "HTTP_FILTER"
+	ApplyToVirtualHost ApplyTo = "VIRTUAL_HOST"
+)
+
+type PatchContext string
+
+const (
+	PatchContextGateway PatchContext = "GATEWAY"
+)
+
+type PatchOperation string
+
+const (
+	PatchOperationMerge        PatchOperation = "MERGE"
+	PatchOperationInsertBefore PatchOperation = "INSERT_BEFORE"
+)
+
+// EnvoyFilterSpec customizes the Envoy configuration generated by Istio
+type EnvoyFilterSpec struct {
+	// Criteria used to select the workloads the patches are applied to
+	WorkloadSelector *WorkloadSelector `json:"workloadSelector,omitempty"`
+	// The patches to apply, in order
+	ConfigPatches []EnvoyConfigObjectPatch `json:"configPatches"`
+}
+
+// WorkloadSelector selects the workloads by their labels
+type WorkloadSelector struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// EnvoyConfigObjectPatch describes the Envoy configuration object to patch and the patch
+type EnvoyConfigObjectPatch struct {
+	// The place in the Envoy configuration the patch is applied to
+	ApplyTo ApplyTo `json:"applyTo"`
+	// Match on the Envoy configuration objects to patch
+	Match *EnvoyConfigObjectMatch `json:"match,omitempty"`
+	// The patch to apply
+	Patch *Patch `json:"patch"`
+}
+
+// EnvoyConfigObjectMatch matches the Envoy configuration objects to patch
+type EnvoyConfigObjectMatch struct {
+	// The traffic the patch applies to
+	Context PatchContext `json:"context,omitempty"`
+	// Match on the listener and its filters
+	Listener *ListenerMatch `json:"listener,omitempty"`
+	// Match on the route configuration and its virtual hosts
+	RouteConfiguration *RouteConfigurationMatch `json:"routeConfiguration,omitempty"`
+}
+
+// ListenerMatch matches a listener and, optionally, one of its filters
+type ListenerMatch struct {
+	FilterChain *FilterChainMatch `json:"filterChain,omitempty"`
+}
+
+// FilterChainMatch matches a filter chain of a listener
+type FilterChainMatch struct {
+	Filter *FilterMatch `json:"filter,omitempty"`
+}
+
+// FilterMatch matches a network filter and, optionally, one of its sub filters
+type FilterMatch struct {
+	Name      string          `json:"name"`
+	SubFilter *SubFilterMatch `json:"subFilter,omitempty"`
+}
+
+// SubFilterMatch matches an HTTP filter of the HTTP connection manager
+type SubFilterMatch struct {
+	Name string `json:"name"`
+}
+
+// RouteConfigurationMatch matches a route configuration and, optionally, one of its virtual hosts
+type RouteConfigurationMatch struct {
+	Vhost *VirtualHostMatch `json:"vhost,omitempty"`
+}
+
+// VirtualHostMatch matches a virtual host by its name, which has the host:port format
+type VirtualHostMatch struct {
+	Name string `json:"name"`
+}
+
+// Patch is the operation and the Envoy configuration it applies
+type Patch struct {
+	Operation PatchOperation `json:"operation"`
+	// The Envoy configuration in its JSON representation
+	Value *runtime.RawExtension `json:"value,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// EnvoyFilter is the Schema for the envoyfilters API
+type EnvoyFilter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec EnvoyFilterSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnvoyFilterList contains a list of EnvoyFilter
+type EnvoyFilterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EnvoyFilter `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EnvoyFilter{}, &EnvoyFilterList{})
+}
@@ -0,0 +1,71 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Location string
+
+const (
+	LocationMeshExternal Location = "MESH_EXTERNAL"
+	LocationMeshInternal Location = "MESH_INTERNAL"
+)
+
+type Resolution string
+
+const (
+	ResolutionNone   Resolution = "NONE"
+	ResolutionStatic Resolution = "STATIC"
+	ResolutionDNS    Resolution = "DNS"
+)
+
+// ServiceEntrySpec adds additional entries into Istio's internal service registry
+type ServiceEntrySpec struct {
+	// The hosts associated with the ServiceEntry
+	Hosts []string `json:"hosts"`
+	// The virtual IP addresses associated with the service
+	Addresses []string `json:"addresses,omitempty"`
+	// The ports associated with the external service
+	Ports []Port `json:"ports"`
+	// Specify whether the service should be considered external to the mesh or part of the mesh
+	Location Location `json:"location,omitempty"`
+	// Service discovery mode for the hosts
+	Resolution Resolution `json:"resolution,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ServiceEntry is the Schema for the serviceentries API
+type ServiceEntry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceEntrySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServiceEntryList contains a list of ServiceEntry
+type ServiceEntryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceEntry `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceEntry{}, &ServiceEntryList{})
+}
@@ -0,0 +1,90 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RuleSpec defines the desired state of Rule
+type RuleSpec struct {
+	Upstream       *Upstream  `json:"upstream"`
+	Match          *Match     `json:"match"`
+	Authenticators []*Handler `json:"authenticators,omitempty"`
+	Authorizer     *Handler   `json:"authorizer,omitempty"`
+	Mutator        *Handler   `json:"mutator,omitempty"`
+}
+
+// Upstream represents the location of a server where requests matching a rule should be forwarded to
+type Upstream struct {
+	// URL defines the target URL for incoming requests
+	URL string `json:"url"`
+	// StripPath replaces the provided path prefix when forwarding the requested URL to the upstream URL
+	StripPath *string `json:"stripPath,omitempty"`
+	// PreserveHost includes the host and port of the url value if set to false
+	PreserveHost *bool `json:"preserveHost,omitempty"`
+}
+
+// Match defines the URL(s) that an access rule should match
+type Match struct {
+	// URL is the URL pattern that should be matched. It supports regex templates.
+	URL string `json:"url"`
+	// Methods represent an array of HTTP methods (e.g. GET, POST, PUT, DELETE, ...)
+	Methods []string `json:"methods"`
+}
+
+// Handler represents an Oathkeeper routine that operates on incoming requests
+type Handler struct {
+	// Name is the name of a handler
+	Name string `json:"handler"`
+	// Config configures the handler. Configuration keys vary per handler.
+	Config *runtime.RawExtension `json:"config,omitempty"`
+}
+
+// RuleStatus defines the observed state of Rule
+type RuleStatus struct {
+	Validation *Validation `json:"validationStatus,omitempty"`
+}
+
+// Validation defines the validation state of Rule
+type Validation struct {
+	Valid *bool   `json:"valid,omitempty"`
+	Error *string `json:"validationError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// Rule is the Schema for the rules API
+type Rule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RuleSpec   `json:"spec,omitempty"`
+	Status RuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RuleList contains a list of Rule
+type RuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Rule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Rule{}, &RuleList{})
+}
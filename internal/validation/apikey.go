@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// queryParamNameRegex matches the query parameter names the key can be read from
+var queryParamNameRegex = regexp.MustCompile("^[A-Za-z0-9_.-]+$")
+
+type apiKey struct{}
+
+func (a *apiKey) Validate(config *runtime.RawExtension) error {
+	var template gatewayv2alpha1.APIKeyModeConfig
+
+	if !configNotEmpty(config) {
+		return fmt.Errorf("supplied config cannot be empty")
+	}
+
+	err := json.Unmarshal(config.Raw, &template)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if template.Header != "" && template.QueryParam != "" {
+		return fmt.Errorf("supplied config is invalid: the key is read either from a header or from a query parameter")
+	}
+	if template.Header != "" && !lowercaseHeaderNameRegex.MatchString(template.Header) {
+		return fmt.Errorf("supplied config is invalid: header %q must be lowercase", template.Header)
+	}
+	if template.QueryParam != "" && !queryParamNameRegex.MatchString(template.QueryParam) {
+		return fmt.Errorf("supplied config is invalid: query parameter name %q is invalid", template.QueryParam)
+	}
+	if template.SecretSelector == nil {
+		return fmt.Errorf("supplied config is invalid: secretSelector cannot be empty")
+	}
+	_, err = metav1.LabelSelectorAsSelector(template.SecretSelector)
+	if err != nil {
+		return fmt.Errorf("supplied config is invalid: secretSelector is invalid: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestAPIKeyValidate(t *testing.T) {
+	strategy, err := validation.NewFactory(log).StrategyFor(gatewayv2alpha1.APIKEY)
+	assert.NilError(t, err)
+
+	valid := &runtime.RawExtension{Raw: []byte(`{"header":"x-tooling-key","secretSelector":{"matchLabels":{"app":"tooling"}}}`)}
+	assert.NilError(t, strategy.Validate(valid))
+
+	validQueryParam := &runtime.RawExtension{Raw: []byte(`{"queryParam":"api_key","secretSelector":{"matchExpressions":[{"key":"app","operator":"Exists"}]}}`)}
+	assert.NilError(t, strategy.Validate(validQueryParam))
+
+	assert.Error(t, strategy.Validate(nil), "supplied config cannot be empty")
+
+	both := &runtime.RawExtension{Raw: []byte(`{"header":"x-api-key","queryParam":"api_key","secretSelector":{}}`)}
+	assert.Error(t, strategy.Validate(both), "supplied config is invalid: the key is read either from a header or from a query parameter")
+
+	badHeader := &runtime.RawExtension{Raw: []byte(`{"header":"X-Api-Key","secretSelector":{}}`)}
+	assert.Error(t, strategy.Validate(badHeader), `supplied config is invalid: header "X-Api-Key" must be lowercase`)
+
+	badQueryParam := &runtime.RawExtension{Raw: []byte(`{"queryParam":"api key","secretSelector":{}}`)}
+	assert.Error(t, strategy.Validate(badQueryParam), `supplied config is invalid: query parameter name "api key" is invalid`)
+
+	noSelector := &runtime.RawExtension{Raw: []byte(`{"header":"x-api-key"}`)}
+	assert.Error(t, strategy.Validate(noSelector), "supplied config is invalid: secretSelector cannot be empty")
+
+	badSelector := &runtime.RawExtension{Raw: []byte(`{"secretSelector":{"matchExpressions":[{"key":"app","operator":"Maybe"}]}}`)}
+	assert.ErrorContains(t, strategy.Validate(badSelector), "supplied config is invalid: secretSelector is invalid")
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// validateAuthenticators verifies the chain of the strategy and the authenticators of the Gate, each authenticator
+// being validated like the auth strategy of the same name
+func (f *factory) validateAuthenticators(auth *gatewayv2alpha1.AuthStrategy) error {
+	if len(auth.Authenticators) == 0 {
+		return nil
+	}
+
+	chain := append([]gatewayv2alpha1.Authenticator{{Name: auth.Name, Config: auth.Config}}, auth.Authenticators...)
+	oauthAuthenticators := 0
+	for i, authenticator := range chain {
+		if authenticator.Name == nil {
+			return fmt.Errorf("supplied authenticators are invalid: authenticator %d must define its name", i)
+		}
+
+		switch *authenticator.Name {
+		case gatewayv2alpha1.OAUTH:
+			oauthAuthenticators++
+		case gatewayv2alpha1.JWT:
+			err := validateChainedJWT(authenticator.Config)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("supplied authenticators are invalid: %s cannot be chained, only JWT and OAUTH", *authenticator.Name)
+		}
+
+		// The config of the strategy itself is validated by the caller
+		if i == 0 {
+			continue
+		}
+		strategy, err := f.StrategyFor(*authenticator.Name)
+		if err != nil {
+			return err
+		}
+		err = strategy.Validate(authenticator.Config)
+		if err != nil {
+			return fmt.Errorf("supplied authenticators are invalid: %s authenticator %d: %v", *authenticator.Name, i, err)
+		}
+	}
+
+	if oauthAuthenticators != 1 {
+		return fmt.Errorf("supplied authenticators are invalid: exactly one OAUTH authenticator must define the paths of the access rules")
+	}
+	return nil
+}
+
+// validateChainedJWT rejects the options of the JWT strategy which the access rules of the auth proxy do not support.
+// Configs which cannot be parsed are rejected by the validation of the strategy.
+func validateChainedJWT(config *runtime.RawExtension) error {
+	var template gatewayv2alpha1.JWTModeConfig
+	if !configNotEmpty(config) || json.Unmarshal(config.Raw, &template) != nil {
+		return nil
+	}
+
+	if len(template.TriggerRules) > 0 {
+		return fmt.Errorf("supplied authenticators are invalid: trigger rules are not supported by chained JWT authenticators")
+	}
+	for _, option := range template.Paths {
+		if len(option.RequiredClaims) > 0 {
+			return fmt.Errorf("supplied authenticators are invalid: required claims of path %s are not supported by chained JWT authenticators", option.Path)
+		}
+	}
+	return nil
+}
+
+// usesAccessRules checks if the requests to the Gate are authenticated by the access rules of the auth proxy
+func usesAccessRules(api *gatewayv2alpha1.Gate) bool {
+	return *api.Spec.Auth.Name == gatewayv2alpha1.OAUTH || len(api.Spec.Auth.Authenticators) > 0
+}
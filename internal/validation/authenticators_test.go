@@ -0,0 +1,98 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestValidateAuthenticators(t *testing.T) {
+	jwtConfig := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","paths":[{"path":"/orders","scopes":["read"]}]}`)}
+	oauthConfig := &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/orders","scopes":["read"]}]}`)}
+
+	gate := func(name string, config *runtime.RawExtension, authenticators ...gatewayv2alpha1.Authenticator) *gatewayv2alpha1.Gate {
+		gateway, serviceName, host := "kyma-gateway.kyma-system.svc.cluster.local", "orders", "orders.kyma.local"
+		port := int32(8080)
+		return &gatewayv2alpha1.Gate{
+			Spec: gatewayv2alpha1.GateSpec{
+				Gateway: &gateway,
+				Service: &gatewayv2alpha1.Service{Name: &serviceName, Host: &host, Port: &port},
+				Auth:    &gatewayv2alpha1.AuthStrategy{Name: &name, Config: config, Authenticators: authenticators},
+			},
+		}
+	}
+	authenticator := func(name string, config *runtime.RawExtension) gatewayv2alpha1.Authenticator {
+		return gatewayv2alpha1.Authenticator{Name: &name, Config: config}
+	}
+	factory := validation.NewFactory(log)
+
+	assert.NilError(t, factory.ValidateGate(gate(gatewayv2alpha1.JWT, jwtConfig, authenticator(gatewayv2alpha1.OAUTH, oauthConfig))))
+	assert.NilError(t, factory.ValidateGate(gate(gatewayv2alpha1.OAUTH, oauthConfig, authenticator(gatewayv2alpha1.JWT, jwtConfig))))
+
+	assert.Error(t, factory.ValidateGate(gate(gatewayv2alpha1.PASSTHROUGH, nil, authenticator(gatewayv2alpha1.OAUTH, oauthConfig))),
+		"supplied authenticators are invalid: PASSTHROUGH cannot be chained, only JWT and OAUTH")
+
+	assert.Error(t, factory.ValidateGate(gate(gatewayv2alpha1.JWT, jwtConfig, authenticator(gatewayv2alpha1.JWT, jwtConfig))),
+		"supplied authenticators are invalid: exactly one OAUTH authenticator must define the paths of the access rules")
+
+	noIssuer := &runtime.RawExtension{Raw: []byte(`{"jwksUri":"https://dex.kyma.local/keys"}`)}
+	assert.Error(t, factory.ValidateGate(gate(gatewayv2alpha1.OAUTH, oauthConfig, authenticator(gatewayv2alpha1.JWT, noIssuer))),
+		"supplied authenticators are invalid: JWT authenticator 1: supplied config is invalid: issuer cannot be empty")
+
+	triggerRules := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","triggerRules":[{"excludedPaths":[{"exact":"/healthz"}]}]}`)}
+	assert.Error(t, factory.ValidateGate(gate(gatewayv2alpha1.OAUTH, oauthConfig, authenticator(gatewayv2alpha1.JWT, triggerRules))),
+		"supplied authenticators are invalid: trigger rules are not supported by chained JWT authenticators")
+
+	requiredClaims := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","paths":[{"path":"/orders","requiredClaims":{"team":"orders"}}]}`)}
+	assert.Error(t, factory.ValidateGate(gate(gatewayv2alpha1.OAUTH, oauthConfig, authenticator(gatewayv2alpha1.JWT, requiredClaims))),
+		"supplied authenticators are invalid: required claims of path /orders are not supported by chained JWT authenticators")
+
+	assert.NilError(t, factory.ValidateGate(gate(gatewayv2alpha1.JWT, jwtConfig)))
+	assert.NilError(t, factory.ValidateGate(gate(gatewayv2alpha1.JWT, requiredClaims)))
+	regexPath := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","paths":[{"path":"/orders/*/items","scopes":["read"]}]}`)}
+	assert.Error(t, factory.ValidateGate(gate(gatewayv2alpha1.JWT, regexPath)),
+		"supplied config is invalid: path /orders/*/items must start with / and be matched exactly, or by prefix with a trailing *")
+
+	noScopes := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys"}`)}
+	otherService, otherPort := "billing", int32(9090)
+	jwtGate := gate(gatewayv2alpha1.JWT, noScopes)
+	jwtGate.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:    &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service: &gatewayv2alpha1.RouteService{Name: jwtGate.Spec.Service.Name, Port: jwtGate.Spec.Service.Port},
+		},
+	}
+	assert.NilError(t, factory.ValidateGate(jwtGate))
+	jwtGate.Spec.Routes[0].Service.Port = &otherPort
+	assert.Error(t, factory.ValidateGate(jwtGate),
+		"supplied routes are invalid: service orders:9090 is not the service of the Gate, the only one the JWT strategy verifies the tokens on")
+	jwtGate.Spec.Routes[0].Service = &gatewayv2alpha1.RouteService{Name: &otherService, Port: jwtGate.Spec.Service.Port}
+	assert.Error(t, factory.ValidateGate(jwtGate),
+		"supplied routes are invalid: service billing:8080 is not the service of the Gate, the only one the JWT strategy verifies the tokens on")
+	external := true
+	externalGate := gate(gatewayv2alpha1.JWT, noScopes)
+	externalGate.Spec.Service.IsExternal = &external
+	assert.Error(t, factory.ValidateGate(externalGate), "supplied service is invalid: the JWT strategy cannot verify the tokens on external services")
+	otherNamespace := "billing"
+	jwtGate.Spec.Routes[0].Service = &gatewayv2alpha1.RouteService{Name: jwtGate.Spec.Service.Name, Namespace: &otherNamespace, Port: jwtGate.Spec.Service.Port}
+	assert.Error(t, factory.ValidateGate(jwtGate),
+		"supplied routes are invalid: service orders:8080 is not the service of the Gate, the only one the JWT strategy verifies the tokens on")
+
+	chained := gate(gatewayv2alpha1.JWT, jwtConfig, authenticator(gatewayv2alpha1.OAUTH, oauthConfig))
+	chained.Spec.Routes = []gatewayv2alpha1.Route{
+		{
+			Path:       &gatewayv2alpha1.StringMatch{Prefix: "/orders"},
+			Service:    &gatewayv2alpha1.RouteService{Name: chained.Spec.Service.Name, Port: chained.Spec.Service.Port},
+			Conditions: &gatewayv2alpha1.RouteConditions{Headers: map[string]gatewayv2alpha1.StringMatch{"x-beta": {Exact: "true"}}},
+		},
+	}
+	assert.Error(t, factory.ValidateGate(chained), "supplied routes are invalid: conditions are not supported by the OAUTH strategy")
+
+	chained.Spec.Routes[0].Conditions = nil
+	chained.Spec.Routes[0].Rewrite = &gatewayv2alpha1.Rewrite{URI: "/"}
+	assert.Error(t, factory.ValidateGate(chained),
+		"supplied routes are invalid: traffic, rewrite and headers of the routes are not supported by the OAUTH strategy, only the ones of the Gate")
+}
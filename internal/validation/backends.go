@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+)
+
+// subsetNameRegex matches the DNS labels Istio accepts as subset names
+var subsetNameRegex = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+
+// ValidateBackends verifies that the weights of the backends add up to 100, and that every subset is named and
+// selects its pods consistently. Traffic is split only between in-cluster services the Gate routes to directly, and
+// only between the versions of the service of the Gate with the JWT strategy, whose policies verify the tokens in the
+// sidecars of that service alone.
+func ValidateBackends(api *gatewayv2alpha1.Gate) error {
+	if len(api.Spec.Backends) == 0 {
+		return nil
+	}
+
+	if usesAccessRules(api) {
+		return fmt.Errorf("supplied backends are invalid: traffic splitting is not supported by the OAUTH strategy")
+	}
+	if api.Spec.Service.IsExternal != nil && *api.Spec.Service.IsExternal {
+		return fmt.Errorf("supplied backends are invalid: traffic splitting is not supported for external services")
+	}
+
+	var totalWeight int32
+	subsets := map[string]map[string]map[string]string{}
+	for _, backend := range api.Spec.Backends {
+		if backend.Weight < 0 || backend.Weight > 100 {
+			return fmt.Errorf("supplied backends are invalid: weight must be between 0 and 100")
+		}
+		totalWeight += backend.Weight
+
+		if *api.Spec.Auth.Name == gatewayv2alpha1.JWT && !isGateBackend(api, backend) {
+			return fmt.Errorf("supplied backends are invalid: backends can only select versions of the service of the Gate with the JWT strategy, which verifies the tokens on that service only")
+		}
+
+		if backend.Subset == "" {
+			if len(backend.Labels) > 0 {
+				return fmt.Errorf("supplied backends are invalid: labels require a subset name")
+			}
+			continue
+		}
+		if !subsetNameRegex.MatchString(backend.Subset) {
+			return fmt.Errorf("supplied backends are invalid: subset name %q is invalid", backend.Subset)
+		}
+		if len(backend.Labels) == 0 {
+			return fmt.Errorf("supplied backends are invalid: subset %s must define the labels of its pods", backend.Subset)
+		}
+
+		serviceName := *api.Spec.Service.Name
+		if backend.Name != nil && *backend.Name != "" {
+			serviceName = *backend.Name
+		}
+		if subsets[serviceName] == nil {
+			subsets[serviceName] = map[string]map[string]string{}
+		}
+		if labels, defined := subsets[serviceName][backend.Subset]; defined && !reflect.DeepEqual(labels, backend.Labels) {
+			return fmt.Errorf("supplied backends are invalid: subset %s of service %s is defined with different labels", backend.Subset, serviceName)
+		}
+		subsets[serviceName][backend.Subset] = backend.Labels
+	}
+
+	if totalWeight != 100 {
+		return fmt.Errorf("supplied backends are invalid: weights must add up to 100")
+	}
+	return nil
+}
+
+// isGateBackend checks whether the backend forwards to the service and port of the Gate
+func isGateBackend(api *gatewayv2alpha1.Gate, backend gatewayv2alpha1.Backend) bool {
+	if backend.Name != nil && *backend.Name != "" && *backend.Name != *api.Spec.Service.Name {
+		return false
+	}
+	return backend.Port == nil || api.Spec.Service.Port != nil && *backend.Port == *api.Spec.Service.Port
+}
@@ -0,0 +1,64 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+)
+
+func TestValidateBackends(t *testing.T) {
+	serviceName := "orders"
+	passthrough := gatewayv2alpha1.PASSTHROUGH
+	oauth := gatewayv2alpha1.OAUTH
+	external := true
+
+	gateWith := func(backends ...gatewayv2alpha1.Backend) *gatewayv2alpha1.Gate {
+		return &gatewayv2alpha1.Gate{Spec: gatewayv2alpha1.GateSpec{
+			Service:  &gatewayv2alpha1.Service{Name: &serviceName},
+			Auth:     &gatewayv2alpha1.AuthStrategy{Name: &passthrough},
+			Backends: backends,
+		}}
+	}
+	stable := gatewayv2alpha1.Backend{Subset: "v1", Labels: map[string]string{"version": "v1"}, Weight: 90}
+	canary := gatewayv2alpha1.Backend{Subset: "v2", Labels: map[string]string{"version": "v2"}, Weight: 10}
+
+	assert.NilError(t, validation.ValidateBackends(gateWith()))
+	assert.NilError(t, validation.ValidateBackends(gateWith(stable, canary)))
+
+	oauthGate := gateWith(stable, canary)
+	oauthGate.Spec.Auth.Name = &oauth
+	assert.Error(t, validation.ValidateBackends(oauthGate), "supplied backends are invalid: traffic splitting is not supported by the OAUTH strategy")
+
+	jwt := gatewayv2alpha1.JWT
+	jwtGate := gateWith(stable, canary)
+	jwtGate.Spec.Auth.Name = &jwt
+	assert.NilError(t, validation.ValidateBackends(jwtGate))
+	billing := "billing"
+	jwtGate.Spec.Backends[1].Name = &billing
+	assert.Error(t, validation.ValidateBackends(jwtGate), "supplied backends are invalid: backends can only select versions of the service of the Gate with the JWT strategy, which verifies the tokens on that service only")
+	otherPort := int32(9090)
+	jwtGate.Spec.Backends[1].Name = &serviceName
+	jwtGate.Spec.Backends[1].Port = &otherPort
+	assert.Error(t, validation.ValidateBackends(jwtGate), "supplied backends are invalid: backends can only select versions of the service of the Gate with the JWT strategy, which verifies the tokens on that service only")
+
+	externalGate := gateWith(stable, canary)
+	externalGate.Spec.Service.IsExternal = &external
+	assert.Error(t, validation.ValidateBackends(externalGate), "supplied backends are invalid: traffic splitting is not supported for external services")
+
+	unbalanced := gateWith(stable)
+	assert.Error(t, validation.ValidateBackends(unbalanced), "supplied backends are invalid: weights must add up to 100")
+
+	unnamed := gateWith(gatewayv2alpha1.Backend{Labels: map[string]string{"version": "v1"}, Weight: 100})
+	assert.Error(t, validation.ValidateBackends(unnamed), "supplied backends are invalid: labels require a subset name")
+
+	noLabels := gateWith(gatewayv2alpha1.Backend{Subset: "v1", Weight: 100})
+	assert.Error(t, validation.ValidateBackends(noLabels), "supplied backends are invalid: subset v1 must define the labels of its pods")
+
+	invalidName := gateWith(gatewayv2alpha1.Backend{Subset: "V1", Labels: map[string]string{"version": "v1"}, Weight: 100})
+	assert.Error(t, validation.ValidateBackends(invalidName), `supplied backends are invalid: subset name "V1" is invalid`)
+
+	conflicting := gateWith(stable, gatewayv2alpha1.Backend{Subset: "v1", Labels: map[string]string{"version": "1.0"}, Weight: 10})
+	assert.Error(t, validation.ValidateBackends(conflicting), "supplied backends are invalid: subset v1 of service orders is defined with different labels")
+}
@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+)
+
+// ValidateCors verifies that the CORS policy allows at least one origin, and that its max age is a valid duration
+func ValidateCors(cors *gatewayv2alpha1.CorsPolicy) error {
+	if cors == nil {
+		return nil
+	}
+
+	if len(cors.AllowOrigins) == 0 {
+		return fmt.Errorf("supplied CORS policy is invalid: at least one allowed origin is required")
+	}
+	for _, origin := range cors.AllowOrigins {
+		if origin == "" {
+			return fmt.Errorf("supplied CORS policy is invalid: allowed origin cannot be empty")
+		}
+	}
+
+	if cors.MaxAge != "" {
+		maxAge, err := time.ParseDuration(cors.MaxAge)
+		if err != nil || maxAge < 0 {
+			return fmt.Errorf("supplied CORS policy is invalid: max age %q is not a valid duration", cors.MaxAge)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+)
+
+func TestValidateCors(t *testing.T) {
+	assert.NilError(t, validation.ValidateCors(nil))
+
+	valid := &gatewayv2alpha1.CorsPolicy{
+		AllowOrigins: []string{"https://shop.kyma.local"},
+		AllowMethods: []string{"GET", "POST"},
+		MaxAge:       "24h",
+	}
+	assert.NilError(t, validation.ValidateCors(valid))
+
+	noOrigins := &gatewayv2alpha1.CorsPolicy{}
+	assert.Error(t, validation.ValidateCors(noOrigins), "supplied CORS policy is invalid: at least one allowed origin is required")
+
+	emptyOrigin := &gatewayv2alpha1.CorsPolicy{AllowOrigins: []string{""}}
+	assert.Error(t, validation.ValidateCors(emptyOrigin), "supplied CORS policy is invalid: allowed origin cannot be empty")
+
+	badMaxAge := &gatewayv2alpha1.CorsPolicy{AllowOrigins: []string{"*"}, MaxAge: "one day"}
+	assert.Error(t, validation.ValidateCors(badMaxAge), `supplied CORS policy is invalid: max age "one day" is not a valid duration`)
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+)
+
+// headerNameRegex matches the token characters allowed in HTTP header names
+var headerNameRegex = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+// ValidateRewrite verifies that the rewrite replaces the path with an absolute one, or the authority
+func ValidateRewrite(rewrite *gatewayv2alpha1.Rewrite) error {
+	if rewrite == nil {
+		return nil
+	}
+
+	if rewrite.URI == "" && rewrite.Authority == "" {
+		return fmt.Errorf("supplied rewrite is invalid: uri or authority must be defined")
+	}
+	if rewrite.URI != "" && !strings.HasPrefix(rewrite.URI, "/") {
+		return fmt.Errorf("supplied rewrite is invalid: uri %q must start with /", rewrite.URI)
+	}
+	return nil
+}
+
+// ValidateHeaders verifies that the names of the manipulated request and response headers are valid
+func ValidateHeaders(headers *gatewayv2alpha1.Headers) error {
+	if headers == nil {
+		return nil
+	}
+
+	for _, operations := range []*gatewayv2alpha1.HeaderOperations{headers.Request, headers.Response} {
+		if operations == nil {
+			continue
+		}
+
+		var names []string
+		for name := range operations.Set {
+			names = append(names, name)
+		}
+		for name := range operations.Add {
+			names = append(names, name)
+		}
+		names = append(names, operations.Remove...)
+
+		for _, name := range names {
+			if !headerNameRegex.MatchString(name) {
+				return fmt.Errorf("supplied headers are invalid: header name %q is invalid", name)
+			}
+		}
+	}
+	return nil
+}
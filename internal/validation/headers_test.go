@@ -0,0 +1,42 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+)
+
+func TestValidateRewrite(t *testing.T) {
+	assert.NilError(t, validation.ValidateRewrite(nil))
+	assert.NilError(t, validation.ValidateRewrite(&gatewayv2alpha1.Rewrite{URI: "/"}))
+	assert.NilError(t, validation.ValidateRewrite(&gatewayv2alpha1.Rewrite{Authority: "orders.shop.svc.cluster.local"}))
+
+	empty := &gatewayv2alpha1.Rewrite{}
+	assert.Error(t, validation.ValidateRewrite(empty), "supplied rewrite is invalid: uri or authority must be defined")
+
+	relative := &gatewayv2alpha1.Rewrite{URI: "orders"}
+	assert.Error(t, validation.ValidateRewrite(relative), `supplied rewrite is invalid: uri "orders" must start with /`)
+}
+
+func TestValidateHeaders(t *testing.T) {
+	assert.NilError(t, validation.ValidateHeaders(nil))
+
+	valid := &gatewayv2alpha1.Headers{
+		Request: &gatewayv2alpha1.HeaderOperations{
+			Set:    map[string]string{"x-forwarded-prefix": "/api/orders"},
+			Remove: []string{"cookie"},
+		},
+		Response: &gatewayv2alpha1.HeaderOperations{
+			Add: map[string]string{"cache-control": "no-store"},
+		},
+	}
+	assert.NilError(t, validation.ValidateHeaders(valid))
+
+	invalidName := &gatewayv2alpha1.Headers{Response: &gatewayv2alpha1.HeaderOperations{Remove: []string{"x powered by"}}}
+	assert.Error(t, validation.ValidateHeaders(invalidName), `supplied headers are invalid: header name "x powered by" is invalid`)
+
+	emptyName := &gatewayv2alpha1.Headers{Request: &gatewayv2alpha1.HeaderOperations{Set: map[string]string{"": "value"}}}
+	assert.Error(t, validation.ValidateHeaders(emptyName), `supplied headers are invalid: header name "" is invalid`)
+}
@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ValidateHostClaim verifies that no Gate created earlier exposes the same host on the same gateway. Every Gate
+// routes all the paths of its host, so two Gates claiming the same host on the same gateway always overlap.
+func ValidateHostClaim(ctx context.Context, reader client.Reader, api *gatewayv2alpha1.Gate) error {
+	claimants, err := hostClaimants(ctx, reader, api)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range claimants {
+		if claimedEarlier(other, api) {
+			return hostClaimedError(api, other)
+		}
+	}
+	return nil
+}
+
+// ValidateHostChange verifies that no other Gate exposes the host a Gate moves to, whatever their age. Otherwise an
+// older Gate moving to the host would take it over from the Gate already exposing it.
+func ValidateHostChange(ctx context.Context, reader client.Reader, old, api *gatewayv2alpha1.Gate) error {
+	if hostKey(old) == hostKey(api) {
+		return nil
+	}
+
+	claimants, err := hostClaimants(ctx, reader, api)
+	if err != nil {
+		return err
+	}
+
+	if len(claimants) > 0 {
+		return hostClaimedError(api, claimants[0])
+	}
+	return nil
+}
+
+// hostClaimants returns the other Gates, not being deleted, which expose the host of the Gate on the same gateway
+func hostClaimants(ctx context.Context, reader client.Reader, api *gatewayv2alpha1.Gate) ([]*gatewayv2alpha1.Gate, error) {
+	if hostKey(api) == "" {
+		return nil, nil
+	}
+
+	gates := &gatewayv2alpha1.GateList{}
+	err := reader.List(ctx, gates)
+	if err != nil {
+		return nil, err
+	}
+
+	var claimants []*gatewayv2alpha1.Gate
+	for i := range gates.Items {
+		other := &gates.Items[i]
+		if other.Namespace == api.Namespace && other.Name == api.Name {
+			continue
+		}
+		if !other.ObjectMeta.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if hostKey(other) != hostKey(api) {
+			continue
+		}
+		claimants = append(claimants, other)
+	}
+	return claimants, nil
+}
+
+// hostKey identifies the host of the Gate on its gateway, empty if the Gate does not define both
+func hostKey(api *gatewayv2alpha1.Gate) string {
+	if api.Spec.Service == nil || api.Spec.Service.Host == nil || api.Spec.Gateway == nil {
+		return ""
+	}
+	return *api.Spec.Service.Host + "@" + *api.Spec.Gateway
+}
+
+func hostClaimedError(api, other *gatewayv2alpha1.Gate) error {
+	return fmt.Errorf("host %s on gateway %s is already claimed by Gate %s/%s", *api.Spec.Service.Host, *api.Spec.Gateway, other.Namespace, other.Name)
+}
+
+// claimedEarlier tells if the host claim of the first Gate takes precedence over the one of the second Gate.
+// Gates which are not created yet have no creation timestamp and lose against all the existing ones.
+func claimedEarlier(first, second *gatewayv2alpha1.Gate) bool {
+	if second.CreationTimestamp.IsZero() {
+		return true
+	}
+	if !first.CreationTimestamp.Equal(&second.CreationTimestamp) {
+		return first.CreationTimestamp.Before(&second.CreationTimestamp)
+	}
+	if first.Namespace != second.Namespace {
+		return first.Namespace < second.Namespace
+	}
+	return first.Name < second.Name
+}
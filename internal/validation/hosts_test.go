@@ -0,0 +1,69 @@
+package validation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateHostClaim(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, gatewayv2alpha1.AddToScheme(scheme))
+
+	created := time.Now()
+	first := fixClaim("first", "default", "imgur.kyma.local", "kyma-gateway.kyma-system.svc.cluster.local", created)
+	second := fixClaim("second", "other", "imgur.kyma.local", "kyma-gateway.kyma-system.svc.cluster.local", created.Add(time.Minute))
+	otherGateway := fixClaim("other-gateway", "default", "imgur.kyma.local", "internal-gateway.kyma-system.svc.cluster.local", created.Add(time.Minute))
+	otherHost := fixClaim("other-host", "default", "shop.kyma.local", "kyma-gateway.kyma-system.svc.cluster.local", created.Add(time.Minute))
+	reader := fake.NewFakeClientWithScheme(scheme, first, second, otherGateway, otherHost)
+
+	assert.NilError(t, validation.ValidateHostClaim(context.TODO(), reader, first))
+	assert.Error(t, validation.ValidateHostClaim(context.TODO(), reader, second), "host imgur.kyma.local on gateway kyma-gateway.kyma-system.svc.cluster.local is already claimed by Gate default/first")
+	assert.NilError(t, validation.ValidateHostClaim(context.TODO(), reader, otherGateway))
+	assert.NilError(t, validation.ValidateHostClaim(context.TODO(), reader, otherHost))
+
+	new := fixClaim("new", "default", "shop.kyma.local", "kyma-gateway.kyma-system.svc.cluster.local", time.Time{})
+	assert.Error(t, validation.ValidateHostClaim(context.TODO(), reader, new), "host shop.kyma.local on gateway kyma-gateway.kyma-system.svc.cluster.local is already claimed by Gate default/other-host")
+}
+
+func fixClaim(name, namespace, host, gateway string, created time.Time) *gatewayv2alpha1.Gate {
+	return &gatewayv2alpha1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: gatewayv2alpha1.GateSpec{
+			Service: &gatewayv2alpha1.Service{Host: &host},
+			Gateway: &gateway,
+		},
+	}
+}
+
+func TestValidateHostChange(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, gatewayv2alpha1.AddToScheme(scheme))
+
+	created := time.Now()
+	older := fixClaim("older", "default", "shop.kyma.local", "kyma-gateway.kyma-system.svc.cluster.local", created)
+	newer := fixClaim("newer", "default", "imgur.kyma.local", "kyma-gateway.kyma-system.svc.cluster.local", created.Add(time.Minute))
+	reader := fake.NewFakeClientWithScheme(scheme, older, newer)
+
+	takeover := older.DeepCopy()
+	takeover.Spec.Service.Host = newer.Spec.Service.Host
+	assert.Error(t, validation.ValidateHostChange(context.TODO(), reader, older, takeover), "host imgur.kyma.local on gateway kyma-gateway.kyma-system.svc.cluster.local is already claimed by Gate default/newer")
+
+	free := older.DeepCopy()
+	freeHost := "free.kyma.local"
+	free.Spec.Service.Host = &freeHost
+	assert.NilError(t, validation.ValidateHostChange(context.TODO(), reader, older, free))
+
+	assert.NilError(t, validation.ValidateHostChange(context.TODO(), reader, newer, newer.DeepCopy()))
+}
@@ -1,9 +1,118 @@
 package validation
 
-import "k8s.io/apimachinery/pkg/runtime"
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 type jwt struct{}
 
 func (j *jwt) Validate(config *runtime.RawExtension) error {
+	var template gatewayv2alpha1.JWTModeConfig
+
+	if !configNotEmpty(config) {
+		return fmt.Errorf("supplied config cannot be empty")
+	}
+
+	err := json.Unmarshal(config.Raw, &template)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if template.Issuer == "" {
+		return fmt.Errorf("supplied config is invalid: issuer cannot be empty")
+	}
+	if !isValidURL(template.JWKSURI) {
+		return fmt.Errorf("supplied config is invalid: jwksUri must be an absolute http(s) URL")
+	}
+	var paths []string
+	for _, option := range template.Paths {
+		for claim := range option.RequiredClaims {
+			if claim == "" || strings.ContainsAny(claim, "[]") {
+				return fmt.Errorf("supplied config is invalid: required claim %q of path %s must be a non-empty name without brackets", claim, option.Path)
+			}
+		}
+		paths = append(paths, option.Path)
+	}
+	if hasDuplicates(paths) {
+		return fmt.Errorf("supplied config is invalid: multiple definitions of the same path detected")
+	}
+	for _, rule := range template.TriggerRules {
+		for _, match := range rule.ExcludedPaths {
+			if !isSingleMatch(match) {
+				return fmt.Errorf("supplied config is invalid: excluded path must define exactly one of exact, prefix, suffix or regex")
+			}
+			if match.Regex != "" {
+				return fmt.Errorf("supplied config is invalid: excluded path %s cannot be matched by regex, only exactly, by prefix or by suffix", match.Regex)
+			}
+		}
+	}
+	return nil
+}
+
+func isValidURL(toTest string) bool {
+	u, err := url.ParseRequestURI(toTest)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateJWTGate rejects the paths of the JWT strategy which its AuthorizationPolicy cannot match, and the routes to
+// other services than the service of the Gate. The RequestAuthentication and the
+// AuthorizationPolicy of the strategy verify the JWT alone, in the sidecars of the pods selected by the service of the
+// Gate, so the service cannot be external.
+func validateJWTGate(api *gatewayv2alpha1.Gate) error {
+	if len(api.Spec.Auth.Authenticators) > 0 {
+		return nil
+	}
+
+	if api.Spec.Service.IsExternal != nil && *api.Spec.Service.IsExternal {
+		return fmt.Errorf("supplied service is invalid: the JWT strategy cannot verify the tokens on external services")
+	}
+
+	for _, route := range api.Spec.Routes {
+		if !isGateService(api, route.Service) {
+			return fmt.Errorf("supplied routes are invalid: service %s:%d is not the service of the Gate, the only one the JWT strategy verifies the tokens on", *route.Service.Name, *route.Service.Port)
+		}
+	}
+
+	if !configNotEmpty(api.Spec.Auth.Config) {
+		return nil
+	}
+
+	var template gatewayv2alpha1.JWTModeConfig
+	err := json.Unmarshal(api.Spec.Auth.Config.Raw, &template)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, option := range template.Paths {
+		if !strings.HasPrefix(option.Path, "/") || strings.Contains(strings.TrimSuffix(option.Path, "*"), "*") {
+			return fmt.Errorf("supplied config is invalid: path %s must start with / and be matched exactly, or by prefix with a trailing *", option.Path)
+		}
+	}
 	return nil
 }
+
+// isGateService checks whether the route forwards to the service and port of the Gate
+func isGateService(api *gatewayv2alpha1.Gate, service *gatewayv2alpha1.RouteService) bool {
+	if service.Namespace != nil && *service.Namespace != "" && *service.Namespace != api.Namespace {
+		return false
+	}
+	return *service.Name == *api.Spec.Service.Name && *service.Port == *api.Spec.Service.Port
+}
+
+func isSingleMatch(match gatewayv2alpha1.StringMatch) bool {
+	defined := 0
+	for _, value := range []string{match.Exact, match.Prefix, match.Suffix, match.Regex} {
+		if value != "" {
+			defined++
+		}
+	}
+	return defined == 1
+}
@@ -0,0 +1,41 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestJWTValidate(t *testing.T) {
+	strategy, err := validation.NewFactory(log).StrategyFor(gatewayv2alpha1.JWT)
+	assert.NilError(t, err)
+
+	valid := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","audiences":["foo"],"paths":[{"path":"/foo","scopes":["read"]}],"triggerRules":[{"excludedPaths":[{"exact":"/healthz"}]}]}`)}
+	assert.NilError(t, strategy.Validate(valid))
+
+	assert.Error(t, strategy.Validate(nil), "supplied config cannot be empty")
+
+	noIssuer := &runtime.RawExtension{Raw: []byte(`{"jwksUri":"https://dex.kyma.local/keys"}`)}
+	assert.Error(t, strategy.Validate(noIssuer), "supplied config is invalid: issuer cannot be empty")
+
+	badJWKS := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"keys"}`)}
+	assert.Error(t, strategy.Validate(badJWKS), "supplied config is invalid: jwksUri must be an absolute http(s) URL")
+
+	duplicatedPaths := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","paths":[{"path":"/foo"},{"path":"/foo","scopes":["write"]}]}`)}
+	assert.Error(t, strategy.Validate(duplicatedPaths), "supplied config is invalid: multiple definitions of the same path detected")
+
+	requiredClaims := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","paths":[{"path":"/foo","requiredClaims":{"team":"orders"}}]}`)}
+	assert.NilError(t, strategy.Validate(requiredClaims))
+
+	bracketedClaim := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","paths":[{"path":"/foo","requiredClaims":{"groups[0]":"orders"}}]}`)}
+	assert.Error(t, strategy.Validate(bracketedClaim), `supplied config is invalid: required claim "groups[0]" of path /foo must be a non-empty name without brackets`)
+
+	ambiguousMatch := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","triggerRules":[{"excludedPaths":[{"exact":"/healthz","prefix":"/docs"}]}]}`)}
+	assert.Error(t, strategy.Validate(ambiguousMatch), "supplied config is invalid: excluded path must define exactly one of exact, prefix, suffix or regex")
+
+	regexMatch := &runtime.RawExtension{Raw: []byte(`{"issuer":"https://dex.kyma.local","jwksUri":"https://dex.kyma.local/keys","triggerRules":[{"excludedPaths":[{"regex":"/health.*"}]}]}`)}
+	assert.Error(t, strategy.Validate(regexMatch), "supplied config is invalid: excluded path /health.* cannot be matched by regex, only exactly, by prefix or by suffix")
+}
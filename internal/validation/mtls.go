@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type mtls struct{}
+
+func (m *mtls) Validate(config *runtime.RawExtension) error {
+	var template gatewayv2alpha1.MTLSModeConfig
+
+	if !configNotEmpty(config) {
+		return fmt.Errorf("supplied config cannot be empty")
+	}
+
+	err := json.Unmarshal(config.Raw, &template)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if template.CABundle == nil || template.CABundle.SecretName == "" {
+		return fmt.Errorf("supplied config is invalid: caBundle secret name cannot be empty")
+	}
+	for _, pattern := range append(template.Subjects, template.SubjectAltNames...) {
+		if _, err := regexp.Compile(pattern); pattern == "" || err != nil {
+			return fmt.Errorf("supplied config is invalid: %q is not a valid subject regex", pattern)
+		}
+	}
+	if template.IdentityHeader != "" && !lowercaseHeaderNameRegex.MatchString(template.IdentityHeader) {
+		return fmt.Errorf("supplied config is invalid: identity header %q must be lowercase", template.IdentityHeader)
+	}
+	return nil
+}
+
+// validateMTLSGate verifies that the client certificates of a Gate using the MTLS strategy are verified by its gateway.
+// The CA bundle is stored next to the credential of the gateway, which is named after the Gate, so that a Gate cannot
+// add client CAs to the credentials it does not own.
+func validateMTLSGate(api *gatewayv2alpha1.Gate) error {
+	if api.Spec.TLS == nil || api.Spec.TLS.Mode != gatewayv2alpha1.TLSModeMutual {
+		return fmt.Errorf("supplied TLS configuration is invalid: MTLS strategy requires the MUTUAL mode")
+	}
+	if api.Spec.TLS.SecretName != "" {
+		return fmt.Errorf("supplied TLS configuration is invalid: MTLS strategy requires the secret named after the Gate")
+	}
+	return nil
+}
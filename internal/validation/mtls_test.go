@@ -0,0 +1,53 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMTLSValidate(t *testing.T) {
+	strategy, err := validation.NewFactory(log).StrategyFor(gatewayv2alpha1.MTLS)
+	assert.NilError(t, err)
+
+	valid := &runtime.RawExtension{Raw: []byte(`{"caBundle":{"secretName":"partner-ca"},"subjects":["CN=partner-[a-z]+,O=Example"],"subjectAltNames":["spiffe://partner.example.com/.*"],"identityHeader":"x-partner"}`)}
+	assert.NilError(t, strategy.Validate(valid))
+
+	assert.Error(t, strategy.Validate(nil), "supplied config cannot be empty")
+
+	noCABundle := &runtime.RawExtension{Raw: []byte(`{"subjects":["CN=partner"]}`)}
+	assert.Error(t, strategy.Validate(noCABundle), "supplied config is invalid: caBundle secret name cannot be empty")
+
+	badSubject := &runtime.RawExtension{Raw: []byte(`{"caBundle":{"secretName":"partner-ca"},"subjectAltNames":["partner-(.example.com"]}`)}
+	assert.Error(t, strategy.Validate(badSubject), `supplied config is invalid: "partner-(.example.com" is not a valid subject regex`)
+
+	badHeader := &runtime.RawExtension{Raw: []byte(`{"caBundle":{"secretName":"partner-ca"},"identityHeader":"X-Partner"}`)}
+	assert.Error(t, strategy.Validate(badHeader), `supplied config is invalid: identity header "X-Partner" must be lowercase`)
+}
+
+func TestValidateMTLSGate(t *testing.T) {
+	gateway, serviceName, host, strategy := "kyma-gateway.kyma-system.svc.cluster.local", "orders", "partners.example.com", gatewayv2alpha1.MTLS
+	port := int32(8080)
+	api := &gatewayv2alpha1.Gate{
+		Spec: gatewayv2alpha1.GateSpec{
+			Gateway: &gateway,
+			Service: &gatewayv2alpha1.Service{Name: &serviceName, Host: &host, Port: &port},
+			Auth: &gatewayv2alpha1.AuthStrategy{
+				Name:   &strategy,
+				Config: &runtime.RawExtension{Raw: []byte(`{"caBundle":{"secretName":"partner-ca"}}`)},
+			},
+			TLS: &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeMutual},
+		},
+	}
+	factory := validation.NewFactory(log)
+	assert.NilError(t, factory.ValidateGate(api))
+
+	api.Spec.TLS.SecretName = "istio-ingressgateway-certs"
+	assert.Error(t, factory.ValidateGate(api), "supplied TLS configuration is invalid: MTLS strategy requires the secret named after the Gate")
+
+	api.Spec.TLS = &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeSimple}
+	assert.Error(t, factory.ValidateGate(api), "supplied TLS configuration is invalid: MTLS strategy requires the MUTUAL mode")
+}
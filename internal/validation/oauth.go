@@ -28,17 +28,43 @@ func (o *oauth) Validate(config *runtime.RawExtension) error {
 	if len(template.Paths) == 0 {
 		return fmt.Errorf("supplied config does not match internal template")
 	}
-	if hasDuplicates(template.Paths) {
+	var paths []string
+	for _, option := range template.Paths {
+		paths = append(paths, option.Path)
+	}
+	if hasDuplicates(paths) {
 		return fmt.Errorf("supplied config is invalid: multiple definitions of the same path detected")
 	}
+	for _, option := range template.Paths {
+		err = validatePathStrategy(option)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func hasDuplicates(elements []gatewayv2alpha1.Option) bool {
+// validatePathStrategy checks the strategy overriding the one of the Gate on the path. Public paths bypass the auth
+// proxy, so they cannot require scopes.
+func validatePathStrategy(option gatewayv2alpha1.Option) error {
+	switch option.Strategy {
+	case "", gatewayv2alpha1.OAUTH:
+		return nil
+	case gatewayv2alpha1.PASSTHROUGH:
+		if len(option.Scopes) > 0 {
+			return fmt.Errorf("supplied config is invalid: public path %s cannot require scopes", option.Path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("supplied config is invalid: path %s cannot use the %s strategy, only OAUTH and PASSTHROUGH are supported", option.Path, option.Strategy)
+	}
+}
+
+func hasDuplicates(elements []string) bool {
 	encountered := map[string]bool{}
 	// Create a map of all unique elements.
-	for v := range elements {
-		encountered[elements[v].Path] = true
+	for _, element := range elements {
+		encountered[element] = true
 	}
 	return len(encountered) != len(elements)
 }
@@ -0,0 +1,32 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestOauthValidate(t *testing.T) {
+	strategy, err := validation.NewFactory(log).StrategyFor(gatewayv2alpha1.OAUTH)
+	assert.NilError(t, err)
+
+	valid := &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/healthz","strategy":"PASSTHROUGH","methods":["GET"]},{"path":"/orders/.*","strategy":"OAUTH","scopes":["read"]},{"path":"/docs"}]}`)}
+	assert.NilError(t, strategy.Validate(valid))
+
+	assert.Error(t, strategy.Validate(nil), "supplied config cannot be empty")
+
+	noPaths := &runtime.RawExtension{Raw: []byte(`{"paths":[]}`)}
+	assert.Error(t, strategy.Validate(noPaths), "supplied config does not match internal template")
+
+	duplicatedPaths := &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/foo"},{"path":"/foo","strategy":"PASSTHROUGH"}]}`)}
+	assert.Error(t, strategy.Validate(duplicatedPaths), "supplied config is invalid: multiple definitions of the same path detected")
+
+	scopedPublicPath := &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/healthz","strategy":"PASSTHROUGH","scopes":["read"]}]}`)}
+	assert.Error(t, strategy.Validate(scopedPublicPath), "supplied config is invalid: public path /healthz cannot require scopes")
+
+	unsupportedStrategy := &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/foo","strategy":"JWT"}]}`)}
+	assert.Error(t, strategy.Validate(unsupportedStrategy), "supplied config is invalid: path /foo cannot use the JWT strategy, only OAUTH and PASSTHROUGH are supported")
+}
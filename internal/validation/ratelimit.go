@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"fmt"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+)
+
+// ValidateRateLimit verifies that the rate limit defines at least one limit, and that every limit allows a positive
+// number of requests per a supported unit on at most one path match
+func ValidateRateLimit(rateLimit *gatewayv2alpha1.RateLimit) error {
+	if rateLimit == nil {
+		return nil
+	}
+
+	if len(rateLimit.Limits) == 0 {
+		return fmt.Errorf("supplied rate limit is invalid: at least one limit is required")
+	}
+
+	for _, limit := range rateLimit.Limits {
+		if limit.Path != nil && !isSingleMatch(*limit.Path) {
+			return fmt.Errorf("supplied rate limit is invalid: path must define exactly one of exact, prefix, suffix or regex")
+		}
+		if limit.Requests == 0 {
+			return fmt.Errorf("supplied rate limit is invalid: requests must be greater than zero")
+		}
+		switch limit.Unit {
+		case gatewayv2alpha1.RateLimitUnitSecond, gatewayv2alpha1.RateLimitUnitMinute, gatewayv2alpha1.RateLimitUnitHour:
+		default:
+			return fmt.Errorf("supplied rate limit is invalid: unsupported unit %q", limit.Unit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+)
+
+func TestValidateRateLimit(t *testing.T) {
+	assert.NilError(t, validation.ValidateRateLimit(nil))
+
+	valid := &gatewayv2alpha1.RateLimit{
+		Limits: []gatewayv2alpha1.RateLimitRule{
+			{Path: &gatewayv2alpha1.StringMatch{Prefix: "/orders"}, Methods: []string{"POST"}, Requests: 10, Unit: gatewayv2alpha1.RateLimitUnitMinute},
+			{Requests: 100, Unit: gatewayv2alpha1.RateLimitUnitSecond},
+		},
+	}
+	assert.NilError(t, validation.ValidateRateLimit(valid))
+
+	noLimits := &gatewayv2alpha1.RateLimit{}
+	assert.Error(t, validation.ValidateRateLimit(noLimits), "supplied rate limit is invalid: at least one limit is required")
+
+	badPath := &gatewayv2alpha1.RateLimit{Limits: []gatewayv2alpha1.RateLimitRule{
+		{Path: &gatewayv2alpha1.StringMatch{Prefix: "/orders", Exact: "/orders"}, Requests: 10, Unit: gatewayv2alpha1.RateLimitUnitMinute},
+	}}
+	assert.Error(t, validation.ValidateRateLimit(badPath), "supplied rate limit is invalid: path must define exactly one of exact, prefix, suffix or regex")
+
+	noRequests := &gatewayv2alpha1.RateLimit{Limits: []gatewayv2alpha1.RateLimitRule{{Unit: gatewayv2alpha1.RateLimitUnitMinute}}}
+	assert.Error(t, validation.ValidateRateLimit(noRequests), "supplied rate limit is invalid: requests must be greater than zero")
+
+	badUnit := &gatewayv2alpha1.RateLimit{Limits: []gatewayv2alpha1.RateLimitRule{{Requests: 10, Unit: "day"}}}
+	assert.Error(t, validation.ValidateRateLimit(badUnit), `supplied rate limit is invalid: unsupported unit "day"`)
+}
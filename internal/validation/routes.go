@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+)
+
+// ValidateRoutes verifies that every route defines a single path match, a target service, and valid conditions,
+// traffic policy, rewrite and headers, and that no path match is defined twice with the same conditions
+func ValidateRoutes(routes []gatewayv2alpha1.Route) error {
+	encountered := map[string]bool{}
+
+	for _, route := range routes {
+		if route.Path == nil || !isSingleMatch(*route.Path) {
+			return fmt.Errorf("supplied routes are invalid: path must define exactly one of exact, prefix, suffix or regex")
+		}
+		if route.Service == nil || route.Service.Name == nil || *route.Service.Name == "" || route.Service.Port == nil {
+			return fmt.Errorf("supplied routes are invalid: service name and port are required")
+		}
+
+		err := validateConditions(route.Conditions)
+		if err != nil {
+			return err
+		}
+
+		// The maps of the conditions are marshalled with sorted keys, identifying equal conditions
+		key, err := json.Marshal(struct {
+			Path       *gatewayv2alpha1.StringMatch
+			Conditions *gatewayv2alpha1.RouteConditions
+		}{route.Path, route.Conditions})
+		if err != nil {
+			return err
+		}
+		if encountered[string(key)] {
+			return fmt.Errorf("supplied routes are invalid: multiple definitions of the same path detected")
+		}
+		encountered[string(key)] = true
+
+		err = ValidateTrafficPolicy(route.Traffic)
+		if err != nil {
+			return err
+		}
+		err = ValidateRewrite(route.Rewrite)
+		if err != nil {
+			return err
+		}
+		err = ValidateHeaders(route.Headers)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lowercaseHeaderNameRegex matches the header names Istio accepts in matches
+var lowercaseHeaderNameRegex = regexp.MustCompile("^[a-z0-9!#$%&'*+.^_`|~-]+$")
+
+// validateConditions verifies that the conditions match valid lowercase headers, and valid names of the query
+// parameter and cookie, which are matched within the path and cookie headers
+func validateConditions(conditions *gatewayv2alpha1.RouteConditions) error {
+	if conditions == nil {
+		return nil
+	}
+
+	for name, match := range conditions.Headers {
+		if !lowercaseHeaderNameRegex.MatchString(name) {
+			return fmt.Errorf("supplied routes are invalid: header name %q must be lowercase", name)
+		}
+		if !isSingleMatch(match) {
+			return fmt.Errorf("supplied routes are invalid: header %s must define exactly one of exact, prefix, suffix or regex", name)
+		}
+	}
+	if _, defined := conditions.Headers["cookie"]; defined && conditions.Cookie != nil {
+		return fmt.Errorf("supplied routes are invalid: cookie header cannot be matched together with a cookie")
+	}
+
+	if param := conditions.QueryParam; param != nil {
+		if param.Name == "" || strings.ContainsAny(param.Name, "&=?#") {
+			return fmt.Errorf("supplied routes are invalid: query parameter name %q is invalid", param.Name)
+		}
+		if !isSingleMatch(param.Value) {
+			return fmt.Errorf("supplied routes are invalid: query parameter %s must define exactly one of exact, prefix, suffix or regex", param.Name)
+		}
+	}
+
+	if cookie := conditions.Cookie; cookie != nil {
+		if cookie.Name == "" || strings.ContainsAny(cookie.Name, "=; ") {
+			return fmt.Errorf("supplied routes are invalid: cookie name %q is invalid", cookie.Name)
+		}
+		if !isSingleMatch(cookie.Value) {
+			return fmt.Errorf("supplied routes are invalid: cookie %s must define exactly one of exact, prefix, suffix or regex", cookie.Name)
+		}
+	}
+	return nil
+}
+
+// hasConditions checks if any of the routes matches the requests on more than their path
+func hasConditions(routes []gatewayv2alpha1.Route) bool {
+	for _, route := range routes {
+		if route.Conditions != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRouteOverrides checks if any of the routes overrides the traffic policy or headers of the Gate, or rewrites
+// the requests
+func hasRouteOverrides(routes []gatewayv2alpha1.Route) bool {
+	for _, route := range routes {
+		if route.Traffic != nil || route.Rewrite != nil || route.Headers != nil {
+			return true
+		}
+	}
+	return false
+}
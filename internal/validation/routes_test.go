@@ -0,0 +1,82 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+)
+
+func TestValidateRoutes(t *testing.T) {
+	name := "orders"
+	var port int32 = 8080
+	service := &gatewayv2alpha1.RouteService{Name: &name, Port: &port}
+
+	valid := []gatewayv2alpha1.Route{
+		{Path: &gatewayv2alpha1.StringMatch{Prefix: "/orders"}, Service: service},
+		{Path: &gatewayv2alpha1.StringMatch{Exact: "/orders"}, Service: service},
+	}
+	assert.NilError(t, validation.ValidateRoutes(valid))
+	assert.NilError(t, validation.ValidateRoutes(nil))
+
+	noMatch := []gatewayv2alpha1.Route{
+		{Path: &gatewayv2alpha1.StringMatch{}, Service: service},
+	}
+	assert.Error(t, validation.ValidateRoutes(noMatch), "supplied routes are invalid: path must define exactly one of exact, prefix, suffix or regex")
+
+	noService := []gatewayv2alpha1.Route{
+		{Path: &gatewayv2alpha1.StringMatch{Prefix: "/orders"}},
+	}
+	assert.Error(t, validation.ValidateRoutes(noService), "supplied routes are invalid: service name and port are required")
+
+	duplicated := []gatewayv2alpha1.Route{
+		{Path: &gatewayv2alpha1.StringMatch{Prefix: "/orders"}, Service: service},
+		{Path: &gatewayv2alpha1.StringMatch{Prefix: "/orders"}, Service: service},
+	}
+	assert.Error(t, validation.ValidateRoutes(duplicated), "supplied routes are invalid: multiple definitions of the same path detected")
+}
+
+func TestValidateRouteConditions(t *testing.T) {
+	name := "orders-beta"
+	var port int32 = 8080
+	service := &gatewayv2alpha1.RouteService{Name: &name, Port: &port}
+	path := &gatewayv2alpha1.StringMatch{Prefix: "/"}
+	routeWith := func(conditions *gatewayv2alpha1.RouteConditions) []gatewayv2alpha1.Route {
+		return []gatewayv2alpha1.Route{
+			{Path: path, Service: service},
+			{Path: path, Service: service, Conditions: conditions},
+		}
+	}
+
+	valid := &gatewayv2alpha1.RouteConditions{
+		Headers:    map[string]gatewayv2alpha1.StringMatch{"x-beta-user": {Exact: "true"}},
+		QueryParam: &gatewayv2alpha1.NamedStringMatch{Name: "variant", Value: gatewayv2alpha1.StringMatch{Exact: "b"}},
+		Cookie:     &gatewayv2alpha1.NamedStringMatch{Name: "ab-group", Value: gatewayv2alpha1.StringMatch{Prefix: "beta"}},
+	}
+	assert.NilError(t, validation.ValidateRoutes(routeWith(valid)))
+
+	duplicated := append(routeWith(valid), gatewayv2alpha1.Route{Path: path, Service: service, Conditions: valid.DeepCopy()})
+	assert.Error(t, validation.ValidateRoutes(duplicated), "supplied routes are invalid: multiple definitions of the same path detected")
+
+	uppercase := &gatewayv2alpha1.RouteConditions{Headers: map[string]gatewayv2alpha1.StringMatch{"X-Beta-User": {Exact: "true"}}}
+	assert.Error(t, validation.ValidateRoutes(routeWith(uppercase)), `supplied routes are invalid: header name "X-Beta-User" must be lowercase`)
+
+	noHeaderMatch := &gatewayv2alpha1.RouteConditions{Headers: map[string]gatewayv2alpha1.StringMatch{"x-beta-user": {}}}
+	assert.Error(t, validation.ValidateRoutes(routeWith(noHeaderMatch)), "supplied routes are invalid: header x-beta-user must define exactly one of exact, prefix, suffix or regex")
+
+	cookieTwice := &gatewayv2alpha1.RouteConditions{
+		Headers: map[string]gatewayv2alpha1.StringMatch{"cookie": {Regex: ".*"}},
+		Cookie:  &gatewayv2alpha1.NamedStringMatch{Name: "ab-group", Value: gatewayv2alpha1.StringMatch{Exact: "beta"}},
+	}
+	assert.Error(t, validation.ValidateRoutes(routeWith(cookieTwice)), "supplied routes are invalid: cookie header cannot be matched together with a cookie")
+
+	invalidParam := &gatewayv2alpha1.RouteConditions{QueryParam: &gatewayv2alpha1.NamedStringMatch{Name: "a=b", Value: gatewayv2alpha1.StringMatch{Exact: "1"}}}
+	assert.Error(t, validation.ValidateRoutes(routeWith(invalidParam)), `supplied routes are invalid: query parameter name "a=b" is invalid`)
+
+	noParamMatch := &gatewayv2alpha1.RouteConditions{QueryParam: &gatewayv2alpha1.NamedStringMatch{Name: "variant"}}
+	assert.Error(t, validation.ValidateRoutes(routeWith(noParamMatch)), "supplied routes are invalid: query parameter variant must define exactly one of exact, prefix, suffix or regex")
+
+	invalidCookie := &gatewayv2alpha1.RouteConditions{Cookie: &gatewayv2alpha1.NamedStringMatch{Name: "ab group", Value: gatewayv2alpha1.StringMatch{Exact: "1"}}}
+	assert.Error(t, validation.ValidateRoutes(routeWith(invalidCookie)), `supplied routes are invalid: cookie name "ab group" is invalid`)
+}
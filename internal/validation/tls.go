@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"fmt"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+)
+
+// ValidateTLS verifies the TLS mode and issuer of the Gate. The secret of a requested certificate is named after the
+// Gate, so that a Gate cannot take over the secrets of the shared certificate namespace. The encrypted connections passed through to the service
+// cannot be inspected, so the PASSTHROUGH mode supports neither auth nor the features applied to HTTP requests.
+func ValidateTLS(api *gatewayv2alpha1.Gate) error {
+	tls := api.Spec.TLS
+	if tls == nil {
+		return nil
+	}
+
+	switch tls.Mode {
+	case gatewayv2alpha1.TLSModeSimple, gatewayv2alpha1.TLSModeMutual:
+	case gatewayv2alpha1.TLSModePassthrough:
+		if *api.Spec.Auth.Name != gatewayv2alpha1.PASSTHROUGH {
+			return fmt.Errorf("supplied TLS configuration is invalid: PASSTHROUGH mode requires the PASSTHROUGH auth strategy")
+		}
+		if tls.Issuer != nil {
+			return fmt.Errorf("supplied TLS configuration is invalid: PASSTHROUGH mode cannot request a certificate")
+		}
+		if len(api.Spec.Routes) > 0 || api.Spec.RateLimit != nil || api.Spec.Cors != nil || api.Spec.Traffic != nil || api.Spec.Headers != nil {
+			return fmt.Errorf("supplied TLS configuration is invalid: PASSTHROUGH mode does not support routes, rate limit, CORS, traffic policy or headers")
+		}
+	default:
+		return fmt.Errorf("supplied TLS configuration is invalid: mode %q is not one of SIMPLE, MUTUAL or PASSTHROUGH", tls.Mode)
+	}
+
+	if tls.Issuer != nil {
+		if tls.SecretName != "" {
+			return fmt.Errorf("supplied TLS configuration is invalid: secret name cannot be set along with an issuer, the requested certificate is stored in a secret named after the Gate")
+		}
+		if tls.Issuer.Name == "" {
+			return fmt.Errorf("supplied TLS configuration is invalid: issuer name is required")
+		}
+		if tls.Issuer.Kind != "" && tls.Issuer.Kind != "Issuer" && tls.Issuer.Kind != "ClusterIssuer" {
+			return fmt.Errorf("supplied TLS configuration is invalid: issuer kind %q is not one of Issuer or ClusterIssuer", tls.Issuer.Kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+)
+
+func TestValidateTLS(t *testing.T) {
+	gate := func(strategy string, tls *gatewayv2alpha1.TLSConfig) *gatewayv2alpha1.Gate {
+		return &gatewayv2alpha1.Gate{
+			Spec: gatewayv2alpha1.GateSpec{
+				Auth: &gatewayv2alpha1.AuthStrategy{Name: &strategy},
+				TLS:  tls,
+			},
+		}
+	}
+
+	assert.NilError(t, validation.ValidateTLS(gate(gatewayv2alpha1.JWT, nil)))
+
+	simple := &gatewayv2alpha1.TLSConfig{
+		Mode:   gatewayv2alpha1.TLSModeSimple,
+		Issuer: &gatewayv2alpha1.CertificateIssuer{Name: "letsencrypt", Kind: "ClusterIssuer"},
+	}
+	assert.NilError(t, validation.ValidateTLS(gate(gatewayv2alpha1.JWT, simple)))
+
+	passthrough := &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModePassthrough}
+	assert.NilError(t, validation.ValidateTLS(gate(gatewayv2alpha1.PASSTHROUGH, passthrough)))
+
+	badMode := &gatewayv2alpha1.TLSConfig{Mode: "ISTIO_MUTUAL"}
+	assert.Error(t, validation.ValidateTLS(gate(gatewayv2alpha1.JWT, badMode)),
+		`supplied TLS configuration is invalid: mode "ISTIO_MUTUAL" is not one of SIMPLE, MUTUAL or PASSTHROUGH`)
+
+	assert.Error(t, validation.ValidateTLS(gate(gatewayv2alpha1.JWT, passthrough)),
+		"supplied TLS configuration is invalid: PASSTHROUGH mode requires the PASSTHROUGH auth strategy")
+
+	passthroughIssuer := &gatewayv2alpha1.TLSConfig{
+		Mode:   gatewayv2alpha1.TLSModePassthrough,
+		Issuer: &gatewayv2alpha1.CertificateIssuer{Name: "letsencrypt"},
+	}
+	assert.Error(t, validation.ValidateTLS(gate(gatewayv2alpha1.PASSTHROUGH, passthroughIssuer)),
+		"supplied TLS configuration is invalid: PASSTHROUGH mode cannot request a certificate")
+
+	withCors := gate(gatewayv2alpha1.PASSTHROUGH, passthrough)
+	withCors.Spec.Cors = &gatewayv2alpha1.CorsPolicy{AllowOrigins: []string{"*"}}
+	assert.Error(t, validation.ValidateTLS(withCors),
+		"supplied TLS configuration is invalid: PASSTHROUGH mode does not support routes, rate limit, CORS, traffic policy or headers")
+
+	issuerAndSecret := &gatewayv2alpha1.TLSConfig{
+		Mode:       gatewayv2alpha1.TLSModeSimple,
+		SecretName: "istio-ingressgateway-certs",
+		Issuer:     &gatewayv2alpha1.CertificateIssuer{Name: "letsencrypt"},
+	}
+	assert.Error(t, validation.ValidateTLS(gate(gatewayv2alpha1.JWT, issuerAndSecret)),
+		"supplied TLS configuration is invalid: secret name cannot be set along with an issuer, the requested certificate is stored in a secret named after the Gate")
+
+	noIssuerName := &gatewayv2alpha1.TLSConfig{Mode: gatewayv2alpha1.TLSModeMutual, Issuer: &gatewayv2alpha1.CertificateIssuer{}}
+	assert.Error(t, validation.ValidateTLS(gate(gatewayv2alpha1.JWT, noIssuerName)),
+		"supplied TLS configuration is invalid: issuer name is required")
+}
@@ -0,0 +1,62 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+)
+
+// ValidateTrafficPolicy verifies that the timeouts and delays of the traffic policy are valid durations, that the
+// retries are attempted at least once and that the injected faults affect a valid percentage of the requests
+func ValidateTrafficPolicy(traffic *gatewayv2alpha1.TrafficPolicy) error {
+	if traffic == nil {
+		return nil
+	}
+
+	if traffic.Timeout != "" && !isDuration(traffic.Timeout) {
+		return fmt.Errorf("supplied traffic policy is invalid: timeout %q is not a valid duration", traffic.Timeout)
+	}
+
+	if retries := traffic.Retries; retries != nil {
+		if retries.Attempts < 1 {
+			return fmt.Errorf("supplied traffic policy is invalid: retry attempts must be greater than zero")
+		}
+		if !isDuration(retries.PerTryTimeout) {
+			return fmt.Errorf("supplied traffic policy is invalid: per try timeout %q is not a valid duration", retries.PerTryTimeout)
+		}
+	}
+
+	if fault := traffic.Fault; fault != nil {
+		if fault.Delay == nil && fault.Abort == nil {
+			return fmt.Errorf("supplied traffic policy is invalid: fault must define a delay or an abort")
+		}
+		if fault.Delay != nil {
+			if !isPercentage(fault.Delay.Percent) {
+				return fmt.Errorf("supplied traffic policy is invalid: delay percentage must be between 0 and 100")
+			}
+			if !isDuration(fault.Delay.FixedDelay) {
+				return fmt.Errorf("supplied traffic policy is invalid: delay %q is not a valid duration", fault.Delay.FixedDelay)
+			}
+		}
+		if fault.Abort != nil {
+			if !isPercentage(fault.Abort.Percent) {
+				return fmt.Errorf("supplied traffic policy is invalid: abort percentage must be between 0 and 100")
+			}
+			if fault.Abort.HTTPStatus < 200 || fault.Abort.HTTPStatus > 599 {
+				return fmt.Errorf("supplied traffic policy is invalid: abort HTTP status %d is not valid", fault.Abort.HTTPStatus)
+			}
+		}
+	}
+	return nil
+}
+
+// isDuration verifies that the value is a positive duration
+func isDuration(value string) bool {
+	duration, err := time.ParseDuration(value)
+	return err == nil && duration > 0
+}
+
+func isPercentage(value int32) bool {
+	return value >= 0 && value <= 100
+}
@@ -0,0 +1,44 @@
+package validation_test
+
+import (
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	"gotest.tools/assert"
+)
+
+func TestValidateTrafficPolicy(t *testing.T) {
+	assert.NilError(t, validation.ValidateTrafficPolicy(nil))
+
+	valid := &gatewayv2alpha1.TrafficPolicy{
+		Timeout: "10s",
+		Retries: &gatewayv2alpha1.Retries{Attempts: 3, PerTryTimeout: "2s", RetryOn: "5xx,connect-failure"},
+		Fault: &gatewayv2alpha1.FaultInjection{
+			Delay: &gatewayv2alpha1.FaultDelay{Percent: 10, FixedDelay: "5s"},
+			Abort: &gatewayv2alpha1.FaultAbort{Percent: 5, HTTPStatus: 503},
+		},
+	}
+	assert.NilError(t, validation.ValidateTrafficPolicy(valid))
+
+	badTimeout := &gatewayv2alpha1.TrafficPolicy{Timeout: "10"}
+	assert.Error(t, validation.ValidateTrafficPolicy(badTimeout), `supplied traffic policy is invalid: timeout "10" is not a valid duration`)
+
+	noAttempts := &gatewayv2alpha1.TrafficPolicy{Retries: &gatewayv2alpha1.Retries{PerTryTimeout: "2s"}}
+	assert.Error(t, validation.ValidateTrafficPolicy(noAttempts), "supplied traffic policy is invalid: retry attempts must be greater than zero")
+
+	noPerTryTimeout := &gatewayv2alpha1.TrafficPolicy{Retries: &gatewayv2alpha1.Retries{Attempts: 3}}
+	assert.Error(t, validation.ValidateTrafficPolicy(noPerTryTimeout), `supplied traffic policy is invalid: per try timeout "" is not a valid duration`)
+
+	emptyFault := &gatewayv2alpha1.TrafficPolicy{Fault: &gatewayv2alpha1.FaultInjection{}}
+	assert.Error(t, validation.ValidateTrafficPolicy(emptyFault), "supplied traffic policy is invalid: fault must define a delay or an abort")
+
+	badDelayPercent := &gatewayv2alpha1.TrafficPolicy{Fault: &gatewayv2alpha1.FaultInjection{Delay: &gatewayv2alpha1.FaultDelay{Percent: 150, FixedDelay: "5s"}}}
+	assert.Error(t, validation.ValidateTrafficPolicy(badDelayPercent), "supplied traffic policy is invalid: delay percentage must be between 0 and 100")
+
+	badDelay := &gatewayv2alpha1.TrafficPolicy{Fault: &gatewayv2alpha1.FaultInjection{Delay: &gatewayv2alpha1.FaultDelay{Percent: 10, FixedDelay: "soon"}}}
+	assert.Error(t, validation.ValidateTrafficPolicy(badDelay), `supplied traffic policy is invalid: delay "soon" is not a valid duration`)
+
+	badAbortStatus := &gatewayv2alpha1.TrafficPolicy{Fault: &gatewayv2alpha1.FaultInjection{Abort: &gatewayv2alpha1.FaultAbort{Percent: 10, HTTPStatus: 42}}}
+	assert.Error(t, validation.ValidateTrafficPolicy(badAbortStatus), "supplied traffic policy is invalid: abort HTTP status 42 is not valid")
+}
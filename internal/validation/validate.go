@@ -33,11 +33,98 @@ func (f *factory) StrategyFor(strategyName string) (ValidationStrategy, error) {
 	case gatewayv2alpha1.OAUTH:
 		f.Log.Info("OAUTH validation mode detected")
 		return &oauth{}, nil
+	case gatewayv2alpha1.MTLS:
+		f.Log.Info("MTLS validation mode detected")
+		return &mtls{}, nil
+	case gatewayv2alpha1.APIKEY:
+		f.Log.Info("APIKEY validation mode detected")
+		return &apiKey{}, nil
 	default:
 		return nil, fmt.Errorf("Unsupported mode: %s", strategyName)
 	}
 }
 
+// ValidateGate verifies the routes, the backends, the rate limit, the CORS and traffic policies, the headers, the TLS
+// configuration, and the auth strategy configuration and authenticators of the Gate. It is shared by the controller
+// and the admission webhook, so that both report the same errors.
+func (f *factory) ValidateGate(api *gatewayv2alpha1.Gate) error {
+	if api.Spec.Auth == nil || api.Spec.Auth.Name == nil {
+		return fmt.Errorf("auth strategy must be defined")
+	}
+	if api.Spec.Gateway == nil || *api.Spec.Gateway == "" {
+		return fmt.Errorf("gateway must be defined")
+	}
+	if api.Spec.Service == nil || api.Spec.Service.Port == nil {
+		return fmt.Errorf("service port must be defined")
+	}
+
+	err := ValidateRoutes(api.Spec.Routes)
+	if err != nil {
+		return err
+	}
+	// The access rules of OAUTH select the upstream by the path only, and forward the requests as they are
+	if usesAccessRules(api) && hasConditions(api.Spec.Routes) {
+		return fmt.Errorf("supplied routes are invalid: conditions are not supported by the OAUTH strategy")
+	}
+	if usesAccessRules(api) && hasRouteOverrides(api.Spec.Routes) {
+		return fmt.Errorf("supplied routes are invalid: traffic, rewrite and headers of the routes are not supported by the OAUTH strategy, only the ones of the Gate")
+	}
+
+	err = ValidateBackends(api)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateRateLimit(api.Spec.RateLimit)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateCors(api.Spec.Cors)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateTrafficPolicy(api.Spec.Traffic)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateHeaders(api.Spec.Headers)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateTLS(api)
+	if err != nil {
+		return err
+	}
+	if *api.Spec.Auth.Name == gatewayv2alpha1.MTLS {
+		err = validateMTLSGate(api)
+		if err != nil {
+			return err
+		}
+	}
+
+	strategy, err := f.StrategyFor(*api.Spec.Auth.Name)
+	if err != nil {
+		return err
+	}
+
+	err = strategy.Validate(api.Spec.Auth.Config)
+	if err != nil {
+		return err
+	}
+	if *api.Spec.Auth.Name == gatewayv2alpha1.JWT {
+		err = validateJWTGate(api)
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.validateAuthenticators(api.Spec.Auth)
+}
+
 //configNotEmpty Verify if the config object is not empty
 func configNotEmpty(config *runtime.RawExtension) bool {
 	if config == nil {
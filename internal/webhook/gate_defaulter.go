@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultGatePath is the path the Gate defaulting webhook is served at
+const DefaultGatePath = "/mutate-gateway-kyma-project-io-v2alpha1-gate"
+
+// defaultOAuthMethods are allowed on the OAUTH paths which do not list their methods
+var defaultOAuthMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// +kubebuilder:webhook:path=/mutate-gateway-kyma-project-io-v2alpha1-gate,mutating=true,failurePolicy=fail,groups=gateway.kyma-project.io,resources=gates,verbs=create;update,versions=v2alpha1,name=mgate.kb.io
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+
+// GateDefaulter fills in the optional fields of Gates
+type GateDefaulter struct {
+	Client client.Client
+	Log    logr.Logger
+	// DefaultGateway is used for the Gates which do not specify their gateway
+	DefaultGateway string
+	decoder        *admission.Decoder
+}
+
+func (d *GateDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	api := &gatewayv2alpha1.Gate{}
+
+	err := d.decoder.Decode(req, api)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	err = d.Default(ctx, api)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	marshaled, err := json.Marshal(api)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// Default sets the defaults of the unset fields of the Gate
+func (d *GateDefaulter) Default(ctx context.Context, api *gatewayv2alpha1.Gate) error {
+	if (api.Spec.Gateway == nil || *api.Spec.Gateway == "") && d.DefaultGateway != "" {
+		gateway := d.DefaultGateway
+		api.Spec.Gateway = &gateway
+	}
+
+	if api.Spec.Service != nil {
+		if api.Spec.Service.IsExternal == nil {
+			external := false
+			api.Spec.Service.IsExternal = &external
+		}
+
+		if api.Spec.Service.Port == nil && !*api.Spec.Service.IsExternal && api.Spec.Service.Name != nil {
+			port, err := d.servicePort(ctx, api.Namespace, *api.Spec.Service.Name)
+			if err != nil {
+				return err
+			}
+			api.Spec.Service.Port = port
+		}
+	}
+
+	if api.Spec.Auth == nil {
+		return nil
+	}
+	if api.Spec.Auth.Name != nil && *api.Spec.Auth.Name == gatewayv2alpha1.OAUTH {
+		err := defaultOAuthConfig(api.Spec.Auth.Config)
+		if err != nil {
+			return err
+		}
+	}
+	for _, authenticator := range api.Spec.Auth.Authenticators {
+		if authenticator.Name != nil && *authenticator.Name == gatewayv2alpha1.OAUTH {
+			err := defaultOAuthConfig(authenticator.Config)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// servicePort returns the port of the service if it exposes exactly one, nil otherwise
+func (d *GateDefaulter) servicePort(ctx context.Context, namespace, name string) (*int32, error) {
+	service := &corev1.Service{}
+
+	err := d.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, service)
+	if apierrs.IsNotFound(err) {
+		d.Log.Info("Service not found, the port cannot be defaulted", "name", name, "namespace", namespace)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(service.Spec.Ports) != 1 {
+		return nil, nil
+	}
+	port := service.Spec.Ports[0].Port
+	return &port, nil
+}
+
+// defaultOAuthConfig allows the default methods on the paths which do not list them. Only the methods are patched,
+// the other fields of the config are kept as they are. Configs which cannot be parsed are left intact for the
+// validation to reject.
+func defaultOAuthConfig(raw *runtime.RawExtension) error {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil
+	}
+
+	var config map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw.Raw))
+	decoder.UseNumber()
+	if decoder.Decode(&config) != nil {
+		return nil
+	}
+	paths, ok := config["paths"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	changed := false
+	for _, path := range paths {
+		option, ok := path.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		methods, isList := option["methods"].([]interface{})
+		if len(methods) > 0 || (option["methods"] != nil && !isList) {
+			continue
+		}
+		option["methods"] = append([]string{}, defaultOAuthMethods...)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	defaulted, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	raw.Raw = defaulted
+	return nil
+}
+
+// InjectDecoder injects the decoder into the GateDefaulter
+func (d *GateDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
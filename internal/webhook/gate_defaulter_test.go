@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const defaultGateway = "kyma-gateway.kyma-system.svc.cluster.local"
+
+func TestGateDefaulter(t *testing.T) {
+	single := fixService("imgur", corev1.ServicePort{Name: "http", Port: 8080})
+	multiple := fixService("multi", corev1.ServicePort{Name: "http", Port: 8080}, corev1.ServicePort{Name: "grpc", Port: 9090})
+	defaulter := &GateDefaulter{
+		Client:         fake.NewFakeClient(single, multiple),
+		Log:            zap.Logger(true),
+		DefaultGateway: defaultGateway,
+	}
+
+	api := fixGate(gatewayv2alpha1.OAUTH, []byte(`{"paths":[{"path":"/foo","methods":["GET"]},{"path":"/bar"}]}`))
+	api.Spec.Gateway = nil
+	api.Spec.Service.Port = nil
+
+	assert.NilError(t, defaulter.Default(context.TODO(), api))
+	assert.Equal(t, *api.Spec.Gateway, defaultGateway)
+	assert.Equal(t, *api.Spec.Service.Port, int32(8080))
+	assert.Equal(t, *api.Spec.Service.IsExternal, false)
+
+	var config gatewayv2alpha1.OauthModeConfig
+	assert.NilError(t, json.Unmarshal(api.Spec.Auth.Config.Raw, &config))
+	assert.DeepEqual(t, config.Paths[0].Methods, []string{"GET"})
+	assert.DeepEqual(t, config.Paths[1].Methods, defaultOAuthMethods)
+
+	chained := fixGate(gatewayv2alpha1.JWT, []byte(`{"issuer":"https://dex.kyma.local","jwks":[]}`))
+	oauth := gatewayv2alpha1.OAUTH
+	chained.Spec.Auth.Authenticators = []gatewayv2alpha1.Authenticator{
+		{Name: &oauth, Config: &runtime.RawExtension{Raw: []byte(`{"paths":[{"path":"/foo"}]}`)}},
+	}
+	assert.NilError(t, defaulter.Default(context.TODO(), chained))
+	var chainedConfig gatewayv2alpha1.OauthModeConfig
+	assert.NilError(t, json.Unmarshal(chained.Spec.Auth.Authenticators[0].Config.Raw, &chainedConfig))
+	assert.DeepEqual(t, chainedConfig.Paths[0].Methods, defaultOAuthMethods)
+	assert.Equal(t, string(chained.Spec.Auth.Config.Raw), `{"issuer":"https://dex.kyma.local","jwks":[]}`)
+
+	// Fields unknown to the defaulter are kept for the validation to judge
+	unknown := fixGate(gatewayv2alpha1.OAUTH, []byte(`{"paths":[{"path":"/foo","strategy":"PASSTHROUGH","future":{"ttl":1.5}}],"audience":"orders"}`))
+	assert.NilError(t, defaulter.Default(context.TODO(), unknown))
+	assert.Equal(t, string(unknown.Spec.Auth.Config.Raw), `{"audience":"orders","paths":[{"future":{"ttl":1.5},"methods":["GET","POST","PUT","PATCH","DELETE","HEAD","OPTIONS"],"path":"/foo","strategy":"PASSTHROUGH"}]}`)
+
+	ambiguous := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	name := "multi"
+	ambiguous.Spec.Service.Name = &name
+	ambiguous.Spec.Service.Port = nil
+	assert.NilError(t, defaulter.Default(context.TODO(), ambiguous))
+	assert.Assert(t, ambiguous.Spec.Service.Port == nil)
+
+	missing := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	missingName := "missing"
+	missing.Spec.Service.Name = &missingName
+	missing.Spec.Service.Port = nil
+	assert.NilError(t, defaulter.Default(context.TODO(), missing))
+	assert.Assert(t, missing.Spec.Service.Port == nil)
+
+	custom := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	customGateway := "custom-gateway.custom-namespace.svc.cluster.local"
+	custom.Spec.Gateway = &customGateway
+	assert.NilError(t, defaulter.Default(context.TODO(), custom))
+	assert.Equal(t, *custom.Spec.Service.Port, int32(443))
+	assert.Equal(t, *custom.Spec.Gateway, customGateway)
+}
+
+func TestGateDefaulterHandle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, gatewayv2alpha1.AddToScheme(scheme))
+	decoder, err := admission.NewDecoder(scheme)
+	assert.NilError(t, err)
+
+	defaulter := &GateDefaulter{Client: fake.NewFakeClient(), Log: zap.Logger(true), DefaultGateway: defaultGateway}
+	assert.NilError(t, defaulter.InjectDecoder(decoder))
+
+	api := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	api.Spec.Gateway = nil
+
+	response := defaulter.Handle(context.TODO(), fixRequest(t, api))
+	assert.Assert(t, response.Allowed)
+
+	paths := map[string]interface{}{}
+	for _, patch := range response.Patches {
+		paths[patch.Path] = patch.Value
+	}
+	assert.Equal(t, paths["/spec/gateway"], defaultGateway)
+	assert.Equal(t, paths["/spec/service/external"], false)
+}
+
+func fixService(name string, ports ...corev1.ServicePort) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Ports: ports},
+	}
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"github.com/kyma-incubator/api-gateway/internal/validation"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateGatePath is the path the Gate validating webhook is served at
+const ValidateGatePath = "/validate-gateway-kyma-project-io-v2alpha1-gate"
+
+// +kubebuilder:webhook:path=/validate-gateway-kyma-project-io-v2alpha1-gate,mutating=false,failurePolicy=fail,groups=gateway.kyma-project.io,resources=gates,verbs=create;update,versions=v2alpha1,name=vgate.kb.io
+
+// GateValidator rejects Gates which the controller would fail to validate, including the ones claiming a host
+// already claimed by another Gate
+type GateValidator struct {
+	Client  client.Client
+	Log     logr.Logger
+	decoder *admission.Decoder
+}
+
+func (v *GateValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	api := &gatewayv2alpha1.Gate{}
+
+	err := v.decoder.Decode(req, api)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// Updates of Gates being deleted and updates leaving the spec intact, e.g. the removal of the finalizer, are
+	// admitted, so that the Gates stored before a validation rule was tightened can still be finalized
+	var old *gatewayv2alpha1.Gate
+	if req.Operation == admissionv1beta1.Update {
+		old = &gatewayv2alpha1.Gate{}
+		err = v.decoder.DecodeRaw(req.OldObject, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if !api.ObjectMeta.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(old.Spec, api.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	err = validation.NewFactory(v.Log).ValidateGate(api)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if old != nil {
+		err = validation.ValidateHostChange(ctx, v.Client, old, api)
+	} else {
+		err = validation.ValidateHostClaim(ctx, v.Client, api)
+	}
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder into the GateValidator
+func (v *GateValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
+	"gotest.tools/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestGateValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, gatewayv2alpha1.AddToScheme(scheme))
+	decoder, err := admission.NewDecoder(scheme)
+	assert.NilError(t, err)
+
+	validator := &GateValidator{Client: fake.NewFakeClientWithScheme(scheme), Log: zap.Logger(true)}
+	assert.NilError(t, validator.InjectDecoder(decoder))
+
+	passthrough := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	response := validator.Handle(context.TODO(), fixRequest(t, passthrough))
+	assert.Assert(t, response.Allowed)
+
+	oauthNoConfig := fixGate(gatewayv2alpha1.OAUTH, nil)
+	response = validator.Handle(context.TODO(), fixRequest(t, oauthNoConfig))
+	assert.Assert(t, !response.Allowed)
+	assert.Equal(t, string(response.Result.Reason), "supplied config cannot be empty")
+
+	jwtNoIssuer := fixGate(gatewayv2alpha1.JWT, []byte(`{"jwksUri": "https://dex.kyma.local/keys"}`))
+	response = validator.Handle(context.TODO(), fixRequest(t, jwtNoIssuer))
+	assert.Assert(t, !response.Allowed)
+	assert.Equal(t, string(response.Result.Reason), "supplied config is invalid: issuer cannot be empty")
+
+	claimed := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	claimed.Name = "claimed"
+	assert.NilError(t, validator.Client.Create(context.TODO(), claimed))
+	response = validator.Handle(context.TODO(), fixRequest(t, passthrough))
+	assert.Assert(t, !response.Allowed)
+	assert.Equal(t, string(response.Result.Reason), "host imgur.com on gateway kyma-gateway.kyma-system.svc.cluster.local is already claimed by Gate default/claimed")
+
+	noGateway := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	noGateway.Spec.Gateway = nil
+	response = validator.Handle(context.TODO(), fixRequest(t, noGateway))
+	assert.Assert(t, !response.Allowed)
+	assert.Equal(t, string(response.Result.Reason), "gateway must be defined")
+}
+
+func TestGateValidatorUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, gatewayv2alpha1.AddToScheme(scheme))
+	decoder, err := admission.NewDecoder(scheme)
+	assert.NilError(t, err)
+
+	validator := &GateValidator{Client: fake.NewFakeClientWithScheme(scheme), Log: zap.Logger(true)}
+	assert.NilError(t, validator.InjectDecoder(decoder))
+
+	stored := fixGate(gatewayv2alpha1.OAUTH, nil)
+	stored.Finalizers = []string{"gateway.kyma-project.io/subresources"}
+
+	finalized := stored.DeepCopy()
+	finalized.Finalizers = nil
+	response := validator.Handle(context.TODO(), fixUpdateRequest(t, stored, finalized))
+	assert.Assert(t, response.Allowed)
+
+	deleted := stored.DeepCopy()
+	now := metav1.Now()
+	deleted.DeletionTimestamp = &now
+	deleted.Spec.Gateway = nil
+	response = validator.Handle(context.TODO(), fixUpdateRequest(t, stored, deleted))
+	assert.Assert(t, response.Allowed)
+
+	claimed := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	claimed.Name = "claimed"
+	claimedHost := "claimed.com"
+	claimed.Spec.Service.Host = &claimedHost
+	claimed.CreationTimestamp = metav1.Now()
+	assert.NilError(t, validator.Client.Create(context.TODO(), claimed))
+	older := fixGate(gatewayv2alpha1.PASSTHROUGH, nil)
+	takeover := older.DeepCopy()
+	takeover.Spec.Service.Host = &claimedHost
+	response = validator.Handle(context.TODO(), fixUpdateRequest(t, older, takeover))
+	assert.Assert(t, !response.Allowed)
+	assert.Equal(t, string(response.Result.Reason), "host claimed.com on gateway kyma-gateway.kyma-system.svc.cluster.local is already claimed by Gate default/claimed")
+
+	changed := stored.DeepCopy()
+	changed.Spec.Gateway = nil
+	response = validator.Handle(context.TODO(), fixUpdateRequest(t, stored, changed))
+	assert.Assert(t, !response.Allowed)
+	assert.Equal(t, string(response.Result.Reason), "gateway must be defined")
+}
+
+func fixGate(mode string, config []byte) *gatewayv2alpha1.Gate {
+	name := "imgur"
+	host := "imgur.com"
+	var port int32 = 443
+	gateway := defaultGateway
+
+	api := &gatewayv2alpha1.Gate{
+		Spec: gatewayv2alpha1.GateSpec{
+			Service: &gatewayv2alpha1.Service{Name: &name, Host: &host, Port: &port},
+			Gateway: &gateway,
+			Auth:    &gatewayv2alpha1.AuthStrategy{Name: &mode},
+		},
+	}
+	api.APIVersion = "gateway.kyma-project.io/v2alpha1"
+	api.Kind = "Gate"
+	api.Name = "test"
+	api.Namespace = "default"
+	if config != nil {
+		api.Spec.Auth.Config = &runtime.RawExtension{Raw: config}
+	}
+	return api
+}
+
+func fixRequest(t *testing.T, api *gatewayv2alpha1.Gate) admission.Request {
+	raw, err := json.Marshal(api)
+	assert.NilError(t, err)
+
+	return admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func fixUpdateRequest(t *testing.T, old, api *gatewayv2alpha1.Gate) admission.Request {
+	oldRaw, err := json.Marshal(old)
+	assert.NilError(t, err)
+	raw, err := json.Marshal(api)
+	assert.NilError(t, err)
+
+	return admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Update,
+			Object:    runtime.RawExtension{Raw: raw},
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+		},
+	}
+}
@@ -19,13 +19,21 @@ import (
 	"flag"
 	gatewayv2alpha1 "github.com/kyma-incubator/api-gateway/api/v2alpha1"
 	"github.com/kyma-incubator/api-gateway/controllers"
+	gatewaymetrics "github.com/kyma-incubator/api-gateway/internal/metrics"
+	certmanagerv1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/certmanager/v1alpha1"
+	securityv1beta1 "github.com/kyma-incubator/api-gateway/internal/types/istio/security/v1beta1"
+	istiov1alpha3 "github.com/kyma-incubator/api-gateway/internal/types/istio/v1alpha3"
+	rulev1alpha1 "github.com/kyma-incubator/api-gateway/internal/types/ory/v1alpha1"
+	gatewaywebhook "github.com/kyma-incubator/api-gateway/internal/webhook"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
-	networkingv1alpha3 "knative.dev/pkg/apis/istio/v1alpha3"
 	"os"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"time"
 )
 
 var (
@@ -37,16 +45,31 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 
 	_ = gatewayv2alpha1.AddToScheme(scheme)
-	_ = networkingv1alpha3.AddToScheme(scheme)
+	_ = istiov1alpha3.AddToScheme(scheme)
+	_ = rulev1alpha1.AddToScheme(scheme)
+	_ = securityv1beta1.AddToScheme(scheme)
+	_ = certmanagerv1alpha1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var enableWebhooks bool
+	var defaultGateway string
+	var certificateNamespace string
+	var syncPeriod time.Duration
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", true,
+		"Enable the admission webhooks. Disable it when running the controller without serving certificates, e.g. locally.")
+	flag.StringVar(&defaultGateway, "default-gateway", "kyma-gateway.kyma-system.svc.cluster.local",
+		"The gateway used by the Gates which do not specify one.")
+	flag.StringVar(&certificateNamespace, "certificate-namespace", "istio-system",
+		"The namespace of the ingress gateway, where the certificates of the Gates exposed over TLS are issued.")
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
+		"The period the Gates are reconciled at, correcting the drift of the resources generated outside of their namespace and of the services selected by the JWT strategy.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.Logger(true))
@@ -55,6 +78,8 @@ func main() {
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
 		LeaderElection:     enableLeaderElection,
+		NewClient:          controllers.NewClient,
+		SyncPeriod:         &syncPeriod,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -62,14 +87,34 @@ func main() {
 	}
 
 	if err = (&controllers.ApiReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Api"),
+		Client:               mgr.GetClient(),
+		Log:                  ctrl.Log.WithName("controllers").WithName("Api"),
+		Recorder:             mgr.GetEventRecorderFor("api-gateway-controller"),
+		CertificateNamespace: certificateNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Api")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	metrics.Registry.MustRegister(gatewaymetrics.NewGateStatusCollector(mgr.GetClient()))
+
+	if enableWebhooks {
+		mgr.GetWebhookServer().Register(gatewaywebhook.DefaultGatePath, &webhook.Admission{
+			Handler: &gatewaywebhook.GateDefaulter{
+				Client:         mgr.GetClient(),
+				Log:            ctrl.Log.WithName("webhooks").WithName("GateDefaulter"),
+				DefaultGateway: defaultGateway,
+			},
+		})
+		mgr.GetWebhookServer().Register(gatewaywebhook.ValidateGatePath, &webhook.Admission{
+			Handler: &gatewaywebhook.GateValidator{
+				Client: mgr.GetClient(),
+				Log:    ctrl.Log.WithName("webhooks").WithName("GateValidator"),
+			},
+		})
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")